@@ -0,0 +1,30 @@
+// apicheck 检查路由和models与 internal/api/openapi.yaml 是否一致，不一致时以非零状态退出（用于CI）
+//
+// 用法: go run ./cmd/apicheck
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"payment-demo/internal/api"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	api.SetupRoutes(r)
+
+	problems := api.CheckAPIContract(r.Routes())
+	if len(problems) == 0 {
+		fmt.Println("API contract OK")
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%d contract problem(s):\n", len(problems))
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, "  "+p)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,47 @@
+// clientgen 根据 internal/api/openapi.yaml 生成 pkg/client 的请求响应类型和接口方法
+//
+// 用法: go generate ./pkg/client
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"payment-demo/internal/clientgen"
+)
+
+func main() {
+	specPath := flag.String("spec", "internal/api/openapi.yaml", "OpenAPI spec file")
+	outDir := flag.String("out", "pkg/client", "output directory for generated files")
+	flag.Parse()
+
+	spec, err := os.ReadFile(*specPath)
+	if err != nil {
+		fatalf("failed to read spec: %v", err)
+	}
+	files, err := clientgen.Generate(spec)
+	if err != nil {
+		fatalf("failed to generate client: %v", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(*outDir, name)
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			fatalf("failed to write %s: %v", path, err)
+		}
+		fmt.Println("generated", path)
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,80 @@
+// reconcile 将Evonet结算文件与本地数据目录中的支付、退款对账，并保存结果
+//
+// 用法: go run ./cmd/reconcile -file settlement.csv [-from 2025-01-01 -to 2025-01-31] [-v]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"payment-demo/internal/models"
+	"payment-demo/internal/reconcile"
+)
+
+func main() {
+	file := flag.String("file", "", "Evonet settlement/transaction CSV file")
+	fromFlag := flag.String("from", "", "start date (YYYY-MM-DD, inclusive); defaults to the file's date range")
+	toFlag := flag.String("to", "", "end date (YYYY-MM-DD, inclusive)")
+	verbose := flag.Bool("v", false, "print every unmatched item")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var from, to time.Time
+	var err error
+	if *fromFlag != "" {
+		if from, err = time.ParseInLocation("2006-01-02", *fromFlag, time.Local); err != nil {
+			fatalf("invalid -from: %v", err)
+		}
+	}
+	if *toFlag != "" {
+		if to, err = time.ParseInLocation("2006-01-02", *toFlag, time.Local); err != nil {
+			fatalf("invalid -to: %v", err)
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	lines, err := reconcile.ParseSettlementFile(f)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	result, err := reconcile.Run(filepath.Base(*file), lines, from, to)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	fmt.Printf("Reconciliation %s (%d lines)\n", result.ID, result.LineCount)
+	fmt.Printf("  matched:          %d\n", result.Summary.Matched)
+	fmt.Printf("  amount mismatch:  %d\n", result.Summary.AmountMismatch)
+	fmt.Printf("  status mismatch:  %d\n", result.Summary.StatusMismatch)
+	fmt.Printf("  missing locally:  %d\n", result.Summary.MissingLocally)
+	fmt.Printf("  missing upstream: %d\n", result.Summary.MissingUpstream)
+
+	if *verbose {
+		for _, item := range result.Items {
+			if item.Category == models.ReconMatched {
+				continue
+			}
+			fmt.Printf("  [%s] %s %s local=%.2f upstream=%.2f %s\n",
+				item.Category, item.Kind, item.MerchantTransID, item.LocalAmount, item.UpstreamAmount, item.Note)
+		}
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
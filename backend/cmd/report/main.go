@@ -0,0 +1,62 @@
+// report 从本地数据目录导出交易报表
+//
+// 用法: go run ./cmd/report -from 2025-01-01 -to 2025-01-31 -format xlsx -o report.xlsx
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"payment-demo/internal/report"
+)
+
+func main() {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	fromFlag := flag.String("from", yesterday, "start date (YYYY-MM-DD, inclusive)")
+	toFlag := flag.String("to", "", "end date (YYYY-MM-DD, inclusive, defaults to -from)")
+	format := flag.String("format", report.FormatCSV, "output format: csv, xlsx or jsonl")
+	output := flag.String("o", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	if !report.IsValidFormat(*format) {
+		fatalf("unsupported format %q", *format)
+	}
+	if *toFlag == "" {
+		*toFlag = *fromFlag
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", *fromFlag, time.Local)
+	if err != nil {
+		fatalf("invalid -from: %v", err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", *toFlag, time.Local)
+	if err != nil {
+		fatalf("invalid -to: %v", err)
+	}
+	_, to = report.DayRange(to)
+	if !from.Before(to) {
+		fatalf("-from must not be after -to")
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fatalf("failed to create output: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := report.Build(from, to).Write(w, *format); err != nil {
+		fatalf("failed to write report: %v", err)
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -1,50 +1,125 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
-	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"payment-demo/config"
 	"payment-demo/internal/api"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/health"
+	"payment-demo/internal/service"
+	"payment-demo/internal/webhook"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// worker 需要在停机时停止的后台任务
+type worker interface {
+	Start()
+	Stop()
+}
+
 func main() {
 	// 在启动时验证配置
 	cfg := config.Load()
 	log.Printf("Configuration loaded successfully for environment: %s", cfg.Environment)
 
 	r := gin.Default()
+	// 未配置可信代理时不信任任何X-Forwarded-For，ClientIP即连接的对端地址（限流、风控和审计均依赖它）
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
-	// CORS配置
-	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{
-		"http://localhost:5173", "http://localhost:5174", "http://localhost:5175", "http://localhost:5176", "http://localhost:3000",
-		"https://*.vercel.app", "https://vercel.app",
-		"https://*.onrender.com",               // Render域名支持
-		"https://payment-demo-zeta.vercel.app", // 具体的Vercel域名
-		// 部署后请添加您的实际域名
-		// "https://your-app-name.onrender.com",
-	}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"}
-	config.AllowCredentials = true
-	r.Use(cors.New(config))
+	// CORS配置（来源列表见CORS_ALLOWED_ORIGINS等环境变量）
+	r.Use(api.CORS(cfg))
 
 	// 初始化路由
 	api.SetupRoutes(r)
+	for _, problem := range api.CheckAPIContract(r.Routes()) {
+		log.Printf("[OpenAPI] 接口定义与实现不一致: %s", problem)
+	}
+
+	// 后台任务：目录热加载、状态对账、付款轮询、入站webhook处理、商户webhook投递
+	workers := []worker{
+		catalog.NewWatcher(cfg.CatalogPath, cfg.CatalogReloadInterval),
+		service.NewStatusReconciler(cfg),
+		service.NewPayoutPoller(cfg),
+		service.NewWebhookEventProcessor(),
+		webhook.NewDispatcher(cfg),
+	}
+	for _, w := range workers {
+		w.Start()
+	}
 
-	// 获取端口
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           r,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
-		log.Fatal("Failed to start server:", err)
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on port %s", cfg.Port)
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			stopWorkers(workers, cfg.ShutdownTimeout)
+			log.Fatal("Failed to start server:", err)
+		}
+	case <-ctx.Done():
+		stop()
+		shutdown(srv, workers, cfg)
+	}
+}
+
+// shutdown 先报告未就绪，再排空进行中的请求，最后停止后台任务
+func shutdown(srv *http.Server, workers []worker, cfg *config.Config) {
+	log.Printf("Shutting down, draining in-flight requests (timeout %s)", cfg.ShutdownTimeout)
+	health.SetDraining()
+	if cfg.ShutdownDrainDelay > 0 {
+		// 等负载均衡感知到未就绪后再停止接收新连接
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server shutdown incomplete: %v", err)
+	}
+
+	deadline, _ := ctx.Deadline()
+	stopWorkers(workers, time.Until(deadline))
+	log.Printf("Server stopped")
+}
+
+// stopWorkers 按启动的逆序停止后台任务，超时后不再等待
+func stopWorkers(workers []worker, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(workers) - 1; i >= 0; i-- {
+			workers[i].Stop()
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("Background workers did not stop within %s", timeout)
 	}
 }
@@ -0,0 +1,65 @@
+// webhook-replay 通过管理接口将已保存的入站webhook事件重新送入处理流程
+//
+// 用法: go run ./cmd/webhook-replay -server http://localhost:8080 -token $ADMIN_TOKEN <event-id>...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	server := flag.String("server", "http://localhost:8080", "backend base URL")
+	token := flag.String("token", os.Getenv("ADMIN_TOKEN"), "admin token (defaults to $ADMIN_TOKEN)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <event-id>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	failed := false
+	for _, id := range flag.Args() {
+		if err := replay(client, strings.TrimRight(*server, "/"), *token, id); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", id, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func replay(client *http.Client, server, token, id string) error {
+	req, err := http.NewRequest("POST", server+"/api/v1/admin/webhook-events/"+id+"/replay", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	fmt.Printf("%s: %s\n", id, body)
+	return nil
+}
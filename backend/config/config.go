@@ -4,7 +4,10 @@ import (
 	"errors"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -38,6 +41,99 @@ type Config struct {
 	// 前端配置
 	FrontendURL string
 
+	// CORS允许的来源（已合并当前ENVIRONMENT的列表和FrontendURL），支持 https://*.example.com 和 http://localhost:* 通配
+	CORSAllowedOrigins   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	// HTTP服务器配置
+	ReadTimeout        time.Duration // 读取整个请求（含请求体）的超时
+	ReadHeaderTimeout  time.Duration
+	WriteTimeout       time.Duration // 需大于调用Evonet的超时
+	IdleTimeout        time.Duration // keep-alive连接空闲超时
+	ShutdownDrainDelay time.Duration // 收到SIGTERM后先报告未就绪，等待该时长再停止接收新连接
+	ShutdownTimeout    time.Duration // 等待进行中请求和后台任务结束的最长时间
+
+	// 本地数据目录（支付记录等以JSON文件持久化）
+	DataDir string
+
+	// 状态对账任务配置
+	ReconcilerInterval     time.Duration // 扫描间隔
+	ReconcilerPendingAfter time.Duration // 处于pending/authorized超过该时长才查询
+	ReconcilerRatePerSec   int           // 每秒最多查询Evonet的次数
+	InteractionExpiry      time.Duration // LinkPay/Drop-in交互的过期时长
+
+	// 商户webhook投递配置
+	WebhookMaxAttempts int           // 最大投递次数，超过后进入死信
+	WebhookRetryBase   time.Duration // 首次重试等待时长，之后指数增长
+	WebhookTimeout     time.Duration // 单次投递超时
+
+	// 入站webhook签名校验失败时是否返回401（此类事件始终只记录、不处理）
+	WebhookRequireSignature bool
+
+	// 国家/币种/支付方式目录文件（YAML或JSON），为空时使用内置目录
+	CatalogPath           string
+	CatalogReloadInterval time.Duration
+
+	// 服务端生成merchantTransId：为true时总是生成，否则仅在请求未提供时生成
+	GenerateMerchantTransID bool
+	MerchantTransIDPrefix   string
+
+	// returnUrl/webhookUrl允许的主机名（支持 *.example.com 通配），为空时不限制
+	AllowedURLHosts []string
+
+	// 管理接口令牌，为空时禁用/api/v1/admin
+	AdminToken string
+
+	// Direct API风控：规则文件（为空时使用内置规则）和计算卡指纹的密钥（为空时在DATA_DIR生成并保存随机密钥）
+	FraudChecksEnabled     bool
+	FraudRulesPath         string
+	FraudFingerprintSecret string
+
+	// 限流配置，格式为 <次数>/<周期>，如 10/1m
+	RateLimitEnabled bool
+	RateLimitPayment string   // 创建支付、退款
+	RateLimitStatus  string   // 状态与列表查询
+	RateLimitWebhook string   // Evonet webhook回调
+	RateLimitAPIKeys []string // 按X-API-Key而非IP单独计数的客户端密钥
+
+	// 可信反向代理（IP或CIDR），仅信任来自这些地址的X-Forwarded-For；为空时不信任任何代理
+	TrustedProxies []string
+
+	// 就绪检查中Evonet连通性探测的缓存时长和超时
+	HealthProbeTTL     time.Duration
+	HealthProbeTimeout time.Duration
+
+	// Evonet熔断：连续失败（网络错误或5xx）次数阈值（0表示不熔断）和熔断后的冷却时长
+	EvonetBreakerThreshold int
+	EvonetBreakerCooldown  time.Duration
+
+	// 本服务对外的访问地址，用于生成支付链接；支付链接默认有效期（0表示不过期）
+	PublicBaseURL            string
+	PaymentLinkDefaultExpiry time.Duration
+
+	// 二维码中心叠加的logo（PNG/JPEG），为空时不支持logo参数
+	QRLogoPath string
+
+	// 汇率：汇率表文件（YAML/JSON，为空时使用内置静态汇率）、商品定价的基础币种、
+	// 锁定报价的有效期、币种未指定rounding时的舍入方式
+	FXRatesPath    string
+	FXBaseCurrency string
+	FXQuoteTTL     time.Duration
+	FXRounding     string
+
+	// 手续费率表文件（YAML/JSON），为空时使用内置费率表
+	FeeSchedulePath string
+
+	// 出款通道（simulator或evonet）、处理中付款的轮询间隔、模拟器出款完成的时长
+	PayoutProvider       string
+	PayoutPollInterval   time.Duration
+	PayoutSimulatorDelay time.Duration
+
+	// 拒付证据文件的存储目录（为空时使用 DataDir/disputes）、单个文件大小上限
+	DisputeEvidenceDir      string
+	DisputeEvidenceMaxBytes int64
+
 	// 互斥锁，用于环境切换时的线程安全
 	mu sync.RWMutex
 }
@@ -52,10 +148,82 @@ func Load() *Config {
 			log.Println("No .env file found, using system environment variables")
 		}
 
+		environment := getEnv("ENVIRONMENT", "development")
+		frontendURL := getEnv("FRONTEND_URL", "http://localhost:5173")
+		port := getEnv("PORT", "8080")
+
 		globalConfig = &Config{
-			Port:        getEnv("PORT", "8080"),
-			Environment: getEnv("ENVIRONMENT", "development"),
-			FrontendURL: getEnv("FRONTEND_URL", "http://localhost:5173"),
+			Port:          port,
+			Environment:   environment,
+			FrontendURL:   frontendURL,
+			PublicBaseURL: strings.TrimRight(getEnv("PUBLIC_BASE_URL", "http://localhost:"+port), "/"),
+			DataDir:       getEnv("DATA_DIR", "data"),
+
+			CORSAllowedOrigins:   corsOrigins(environment, frontendURL),
+			CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", true),
+			CORSMaxAge:           getEnvDuration("CORS_MAX_AGE", 12*time.Hour),
+
+			ReadTimeout:        getEnvDuration("HTTP_READ_TIMEOUT", 15*time.Second),
+			ReadHeaderTimeout:  getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+			WriteTimeout:       getEnvDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+			IdleTimeout:        getEnvDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+			ShutdownDrainDelay: getEnvDuration("SHUTDOWN_DRAIN_DELAY", 0),
+			ShutdownTimeout:    getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+			ReconcilerInterval:     getEnvDuration("RECONCILER_INTERVAL", time.Minute),
+			ReconcilerPendingAfter: getEnvDuration("RECONCILER_PENDING_AFTER", 10*time.Minute),
+			ReconcilerRatePerSec:   getEnvInt("RECONCILER_RATE_PER_SEC", 2),
+			InteractionExpiry:      getEnvDuration("INTERACTION_EXPIRY", 24*time.Hour),
+
+			WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			WebhookRetryBase:   getEnvDuration("WEBHOOK_RETRY_BASE", 30*time.Second),
+			WebhookTimeout:     getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+			WebhookRequireSignature: getEnvBool("WEBHOOK_REQUIRE_SIGNATURE", true),
+			AdminToken:              os.Getenv("ADMIN_TOKEN"),
+
+			FraudChecksEnabled:     getEnvBool("FRAUD_CHECKS_ENABLED", true),
+			FraudRulesPath:         os.Getenv("FRAUD_RULES_PATH"),
+			FraudFingerprintSecret: os.Getenv("FRAUD_FINGERPRINT_SECRET"),
+
+			RateLimitEnabled: getEnvBool("RATE_LIMIT_ENABLED", true),
+			RateLimitPayment: getEnv("RATE_LIMIT_PAYMENT", "10/1m"),
+			RateLimitStatus:  getEnv("RATE_LIMIT_STATUS", "120/1m"),
+			RateLimitWebhook: getEnv("RATE_LIMIT_WEBHOOK", "600/1m"),
+			RateLimitAPIKeys: getEnvList("RATE_LIMIT_API_KEYS"),
+			TrustedProxies:   getEnvList("TRUSTED_PROXIES"),
+
+			HealthProbeTTL:     getEnvDuration("HEALTH_PROBE_TTL", 30*time.Second),
+			HealthProbeTimeout: getEnvDuration("HEALTH_PROBE_TIMEOUT", 3*time.Second),
+
+			EvonetBreakerThreshold: getEnvInt("EVONET_BREAKER_THRESHOLD", 5),
+			EvonetBreakerCooldown:  getEnvDuration("EVONET_BREAKER_COOLDOWN", 30*time.Second),
+
+			PaymentLinkDefaultExpiry: getEnvDuration("PAYMENT_LINK_DEFAULT_EXPIRY", 7*24*time.Hour),
+
+			QRLogoPath: os.Getenv("QR_LOGO_PATH"),
+
+			FXRatesPath:    os.Getenv("FX_RATES_PATH"),
+			FXBaseCurrency: strings.ToUpper(getEnv("FX_BASE_CURRENCY", "USD")),
+			FXQuoteTTL:     getEnvDuration("FX_QUOTE_TTL", 15*time.Minute),
+			FXRounding:     getEnv("FX_ROUNDING", "half_up"),
+
+			FeeSchedulePath: os.Getenv("FEE_SCHEDULE_PATH"),
+
+			PayoutProvider:       strings.ToLower(getEnv("PAYOUT_PROVIDER", "simulator")),
+			PayoutPollInterval:   getEnvDuration("PAYOUT_POLL_INTERVAL", 30*time.Second),
+			PayoutSimulatorDelay: getEnvDuration("PAYOUT_SIMULATOR_DELAY", time.Minute),
+
+			DisputeEvidenceDir:      os.Getenv("DISPUTE_EVIDENCE_DIR"),
+			DisputeEvidenceMaxBytes: int64(getEnvInt("DISPUTE_EVIDENCE_MAX_BYTES", 10<<20)),
+
+			AllowedURLHosts: getEnvList("ALLOWED_URL_HOSTS"),
+
+			GenerateMerchantTransID: getEnvBool("GENERATE_MERCHANT_TRANS_ID", false),
+			MerchantTransIDPrefix:   getEnv("MERCHANT_TRANS_ID_PREFIX", "trans_"),
+
+			CatalogPath:           os.Getenv("CATALOG_PATH"),
+			CatalogReloadInterval: getEnvDuration("CATALOG_RELOAD_INTERVAL", 30*time.Second),
 
 			// 默认使用Sandbox环境
 			CurrentAPIEnv: Sandbox,
@@ -104,6 +272,13 @@ func (c *Config) SwitchAPIEnvironment(env APIEnvironment) error {
 	return nil
 }
 
+// GetCurrentAPIEnv 获取当前API环境
+func (c *Config) GetCurrentAPIEnv() APIEnvironment {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.CurrentAPIEnv
+}
+
 // GetAPIMode 获取当前API模式显示名称
 func (c *Config) GetAPIMode() string {
 	c.mu.RLock()
@@ -142,3 +317,82 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("Invalid duration for %s: %q, using default %s", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		log.Printf("Invalid integer for %s: %q, using default %d", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		log.Printf("Invalid boolean for %s: %q, using default %t", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvList 读取逗号分隔的列表，忽略空项
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// 未配置CORS_ALLOWED_ORIGINS时使用的默认来源
+// 不包含 *.vercel.app、*.onrender.com 这类公共托管域名的通配：任何人都能在其下部署应用并携带凭证发起请求
+var (
+	defaultCORSOrigins = []string{
+		"https://payment-demo-zeta.vercel.app",
+	}
+	defaultDevCORSOrigins = []string{
+		"http://localhost:*",
+		"http://127.0.0.1:*",
+	}
+)
+
+// corsOrigins 合并 CORS_ALLOWED_ORIGINS、CORS_ALLOWED_ORIGINS_<ENVIRONMENT> 和前端地址，去重后返回
+func corsOrigins(environment, frontendURL string) []string {
+	origins := getEnvList("CORS_ALLOWED_ORIGINS")
+	if os.Getenv("CORS_ALLOWED_ORIGINS") == "" {
+		origins = append(origins, defaultCORSOrigins...)
+		if environment != "production" {
+			origins = append(origins, defaultDevCORSOrigins...)
+		}
+	}
+	origins = append(origins, getEnvList("CORS_ALLOWED_ORIGINS_"+strings.ToUpper(environment))...)
+	if frontendURL != "" {
+		origins = append(origins, strings.TrimRight(frontendURL, "/"))
+	}
+
+	seen := make(map[string]bool, len(origins))
+	unique := origins[:0]
+	for _, origin := range origins {
+		key := strings.ToLower(origin)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, origin)
+		}
+	}
+	return unique
+}
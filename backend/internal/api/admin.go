@@ -0,0 +1,37 @@
+package api
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"payment-demo/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireAdmin 校验管理接口令牌（Authorization: Bearer <token> 或 X-Admin-Token）
+func requireAdmin(c *gin.Context) {
+	expected := config.Load().AdminToken
+	if expected == "" {
+		c.AbortWithStatusJSON(403, gin.H{
+			"success": false,
+			"message": "Admin API is disabled, set ADMIN_TOKEN to enable it",
+		})
+		return
+	}
+
+	token := c.GetHeader("X-Admin-Token")
+	if token == "" {
+		token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		c.AbortWithStatusJSON(401, gin.H{
+			"success": false,
+			"message": "Invalid admin token",
+		})
+		return
+	}
+
+	c.Set(ctxAdmin, true)
+	c.Next()
+}
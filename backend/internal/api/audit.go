@@ -0,0 +1,181 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/audit"
+	"payment-demo/internal/models"
+	"payment-demo/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 上下文中的请求信息
+const (
+	ctxRequestID = "requestID"
+	ctxAdmin     = "admin"
+)
+
+// requestID 透传或生成X-Request-ID，写入响应头供排查和审计关联
+func requestID(c *gin.Context) {
+	id := c.GetHeader("X-Request-ID")
+	if id == "" || len(id) > 128 || !isPrintableASCII(id) {
+		id = utils.GenerateID("req")
+	}
+	c.Set(ctxRequestID, id)
+	c.Header("X-Request-ID", id)
+	c.Next()
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// auditActor 通过管理令牌认证的为admin，登记的API Key为api_key:<指纹>，其余为anonymous
+func auditActor(c *gin.Context) string {
+	if c.GetBool(ctxAdmin) {
+		return "admin"
+	}
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		for _, known := range config.Load().RateLimitAPIKeys {
+			if key == known {
+				sum := sha256.Sum256([]byte(key))
+				return "api_key:" + hex.EncodeToString(sum[:4])
+			}
+		}
+	}
+	return "anonymous"
+}
+
+// recordAudit 以当前请求的身份、请求ID和来源IP写入审计日志
+func recordAudit(c *gin.Context, action, targetType, targetID string, before, after interface{}) {
+	audit.Record(audit.Event{
+		Actor:      auditActor(c),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		RequestID:  c.GetString(ctxRequestID),
+		SourceIP:   c.ClientIP(),
+		Before:     before,
+		After:      after,
+	})
+}
+
+// 查询审计日志
+func listAuditEntries(c *gin.Context) {
+	match, err := auditFilter(c)
+	if err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+
+	entries := audit.Default().Entries(match)
+	// 最新的在前
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(400, gin.H{"success": false, "message": "limit must be a positive integer"})
+			return
+		}
+		entries = entries[:min(n, len(entries))]
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+// 导出审计日志（jsonl保留完整哈希链，可离线校验）
+func exportAuditEntries(c *gin.Context) {
+	match, err := auditFilter(c)
+	if err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+	format := c.DefaultQuery("format", "jsonl")
+	if format != "jsonl" && format != "csv" {
+		c.JSON(400, gin.H{"success": false, "message": "format must be jsonl or csv"})
+		return
+	}
+
+	entries := audit.Default().Entries(match)
+	filename := fmt.Sprintf("audit_%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "jsonl" {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(200)
+		enc := json.NewEncoder(c.Writer)
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				fmt.Printf("[Audit] 导出审计日志失败: %v\n", err)
+				return
+			}
+		}
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(200)
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"seq", "timestamp", "actor", "action", "targetType", "targetId", "requestId", "sourceIp", "before", "after", "prevHash", "hash"})
+	for _, e := range entries {
+		w.Write([]string{
+			strconv.FormatInt(e.Seq, 10), e.Timestamp.Format(time.RFC3339Nano), e.Actor, e.Action,
+			e.TargetType, e.TargetID, e.RequestID, e.SourceIP, string(e.Before), string(e.After), e.PrevHash, e.Hash,
+		})
+	}
+	w.Flush()
+}
+
+// 校验审计日志哈希链
+func verifyAuditLog(c *gin.Context) {
+	result := audit.Default().Verify()
+	status := 200
+	if !result.Valid {
+		status = 409
+	}
+	c.JSON(status, gin.H{
+		"success": result.Valid,
+		"data":    result,
+	})
+}
+
+// auditFilter 解析 action、actor、targetId、from、to 过滤条件
+func auditFilter(c *gin.Context) (func(models.AuditEntry) bool, error) {
+	action := c.Query("action")
+	actor := c.Query("actor")
+	targetID := c.Query("targetId")
+	from, err := parseTimeParam(c.Query("from"), false)
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	to, err := parseTimeParam(c.Query("to"), true)
+	if err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+
+	return func(e models.AuditEntry) bool {
+		return (action == "" || e.Action == action) &&
+			(actor == "" || e.Actor == actor) &&
+			(targetID == "" || e.TargetID == targetID) &&
+			(from.IsZero() || !e.Timestamp.Before(from)) &&
+			(to.IsZero() || e.Timestamp.Before(to))
+	}, nil
+}
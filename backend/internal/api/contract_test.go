@@ -0,0 +1,661 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/service"
+	"payment-demo/internal/store"
+	"payment-demo/pkg/client"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 契约测试：通过生成的客户端驱动全部/api/v1接口，校验响应状态码和JSON结构与openapi.yaml一致
+
+const testAdminToken = "contract-test-admin-token"
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "payment-demo-api-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create data dir: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("DATA_DIR", dir)
+	os.Setenv("ADMIN_TOKEN", testAdminToken)
+	os.Setenv("RATE_LIMIT_ENABLED", "false")
+	gin.SetMode(gin.TestMode)
+
+	evonet := httptest.NewServer(http.HandlerFunc(mockEvonet))
+	cfg := config.Load()
+	cfg.Sandbox.APIURL = evonet.URL
+	cfg.Production.APIURL = evonet.URL
+
+	code := m.Run()
+	evonet.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// mockEvonet 模拟Evonet接口：交互返回支付链接，支付和退款直接成功
+func mockEvonet(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		MerchantOrderInfo struct {
+			MerchantOrderID string `json:"merchantOrderID"`
+		} `json:"merchantOrderInfo"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	result := map[string]interface{}{"code": "S0000", "message": "ok"}
+	var resp map[string]interface{}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/interaction":
+		resp = map[string]interface{}{
+			"sessionID": "session-1",
+			"linkUrl":   "https://pay.example.com/" + body.MerchantOrderInfo.MerchantOrderID,
+			"result":    result,
+		}
+	case strings.Contains(r.URL.Path, "/refund"):
+		resp = map[string]interface{}{"result": result, "refund": map[string]interface{}{"status": "Success"}}
+	default:
+		resp = map[string]interface{}{
+			"result":          result,
+			"payment":         map[string]interface{}{"status": "Captured"},
+			"transactionInfo": map[string]interface{}{"status": "Captured"},
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestAPIContract(t *testing.T) {
+	spec, _, err := loadOpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := newContractRecorder(spec)
+
+	r := gin.New()
+	r.Use(contract.middleware)
+	SetupRoutes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := client.New(srv.URL, client.WithAdminToken(testAdminToken))
+	must := func(what string, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", what, err)
+		}
+	}
+	expectStatus := func(what string, err error, status int) {
+		t.Helper()
+		var apiErr *client.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+			t.Errorf("%s: 期望HTTP %d，得到 %v", what, status, err)
+		}
+	}
+
+	// 配置
+	_, err = c.GetCountries(ctx, client.GetCountriesParams{Lang: "en"})
+	must("GetCountries", err)
+	_, err = c.GetScenarios(ctx)
+	must("GetScenarios", err)
+	_, err = c.GetConfig(ctx)
+	must("GetConfig", err)
+	_, err = c.SwitchEnvironment(ctx, &client.SwitchEnvironmentRequest{Environment: "sandbox"})
+	must("SwitchEnvironment", err)
+	_, err = c.SwitchEnvironment(ctx, &client.SwitchEnvironmentRequest{Environment: "staging"})
+	expectStatus("SwitchEnvironment invalid", err, 400)
+	_, err = client.New(srv.URL).SwitchEnvironment(ctx, &client.SwitchEnvironmentRequest{Environment: "production"})
+	expectStatus("SwitchEnvironment without token", err, 401)
+
+	// 先订阅商户webhook，后续支付事件会生成投递记录
+	endpoint, err := c.CreateWebhookEndpoint(ctx, &client.WebhookEndpointRequest{URL: "https://merchant.example.com/hooks"})
+	must("CreateWebhookEndpoint", err)
+	_, err = c.ListWebhookEndpoints(ctx)
+	must("ListWebhookEndpoints", err)
+	_, err = client.New(srv.URL).ListWebhookEndpoints(ctx)
+	expectStatus("ListWebhookEndpoints without token", err, 401)
+
+	// 支付
+	direct, err := c.CreateDirectPayment(ctx, &client.PaymentRequest{
+		Amount:        1000,
+		Currency:      "JPY",
+		PaymentType:   client.PaymentTypeDirectapi,
+		PaymentMethod: "card",
+		ReturnURL:     "https://example.com/return",
+		CardInfo: &client.CardInfo{
+			CardNumber: "4111111111111111",
+			ExpiryDate: "1230",
+			CVV:        "123",
+			HolderName: "Test User",
+		},
+	})
+	must("CreateDirectPayment", err)
+	_, err = c.CreateDirectPayment(ctx, &client.PaymentRequest{Currency: "JPY", PaymentType: client.PaymentTypeDirectapi})
+	expectStatus("CreateDirectPayment invalid", err, 400)
+
+	link, err := c.CreateInteraction(ctx, &client.PaymentRequest{
+		Amount:      500,
+		Currency:    "JPY",
+		PaymentType: client.PaymentTypeLinkpay,
+		ReturnURL:   "https://example.com/return",
+	})
+	must("CreateInteraction", err)
+	_, err = c.CreateInteraction(ctx, &client.PaymentRequest{
+		Amount:      500,
+		Currency:    "JPY",
+		PaymentType: client.PaymentTypeDirectapi,
+		ReturnURL:   "https://example.com/return",
+	})
+	expectStatus("CreateInteraction directapi", err, 400)
+	// merchantTransId会拼入Evonet接口路径，不能包含 / ? # % 等字符
+	_, err = c.CreateInteraction(ctx, &client.PaymentRequest{
+		Amount:          500,
+		Currency:        "JPY",
+		PaymentType:     client.PaymentTypeLinkpay,
+		MerchantTransID: "../refund?x=1",
+	})
+	expectStatus("CreateInteraction invalid merchantTransId", err, 400)
+	_, err = c.ListPayments(ctx, client.ListPaymentsParams{Limit: 10})
+	must("ListPayments", err)
+	_, err = client.New(srv.URL).ListPayments(ctx, client.ListPaymentsParams{Limit: 10})
+	expectStatus("ListPayments without token", err, 401)
+	_, err = c.GetPaymentStatus(ctx, direct.MerchantTransID)
+	must("GetPaymentStatus", err)
+	_, err = c.GetInteractionStatus(ctx, link.MerchantTransID)
+	must("GetInteractionStatus", err)
+	must("GetPaymentQR", c.GetPaymentQR(ctx, io.Discard, link.MerchantTransID, client.GetPaymentQRParams{Format: "svg"}))
+	_, err = c.RefundPayment(ctx, direct.MerchantTransID, &client.RefundRequest{Amount: 100, Reason: "contract test"})
+	must("RefundPayment", err)
+	// 沙箱支付不能在生产环境下退款
+	_, err = c.SwitchEnvironment(ctx, &client.SwitchEnvironmentRequest{Environment: "production"})
+	must("SwitchEnvironment", err)
+	_, err = c.RefundPayment(ctx, direct.MerchantTransID, &client.RefundRequest{Amount: 100, Reason: "contract test"})
+	expectStatus("RefundPayment other environment", err, 409)
+	_, err = c.SwitchEnvironment(ctx, &client.SwitchEnvironmentRequest{Environment: "sandbox"})
+	must("SwitchEnvironment", err)
+
+	// 汇率
+	_, err = c.GetFXRates(ctx)
+	must("GetFXRates", err)
+	quote, err := c.CreateFXQuote(ctx, &client.FXQuoteRequest{Amount: 10, Currency: "JPY"})
+	must("CreateFXQuote", err)
+	_, err = c.GetFXQuote(ctx, quote.ID)
+	must("GetFXQuote", err)
+
+	// 支付链接
+	paymentLink, err := c.CreatePaymentLink(ctx, &client.PaymentLinkRequest{
+		Amount:    300,
+		Currency:  "JPY",
+		ReturnURL: "https://example.com/return",
+		Metadata:  map[string]string{"order": "A-1"},
+	})
+	must("CreatePaymentLink", err)
+	_, err = c.ListPaymentLinks(ctx, client.ListPaymentLinksParams{})
+	must("ListPaymentLinks", err)
+	_, err = c.GetPaymentLink(ctx, paymentLink.ID)
+	must("GetPaymentLink", err)
+	_, err = c.CheckoutPaymentLink(ctx, paymentLink.ID, &client.PaymentLinkCheckout{})
+	must("CheckoutPaymentLink", err)
+	_, err = c.ListPaymentLinkPayments(ctx, paymentLink.ID)
+	must("ListPaymentLinkPayments", err)
+	// 分享地址GET只展示确认页，不创建支付；提交后才重定向到LinkPay
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	before := len(service.LinkPayments(paymentLink.ID))
+	resp, err := noRedirect.Get(srv.URL + "/pay/" + paymentLink.ID)
+	must("GET /pay/:id", err)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || len(service.LinkPayments(paymentLink.ID)) != before {
+		t.Errorf("GET /pay/:id: HTTP %d，不应创建支付", resp.StatusCode)
+	}
+	resp, err = noRedirect.PostForm(srv.URL+"/pay/"+paymentLink.ID, nil)
+	must("POST /pay/:id", err)
+	resp.Body.Close()
+	if resp.StatusCode != 303 || !strings.HasPrefix(resp.Header.Get("Location"), "https://pay.example.com/") {
+		t.Errorf("POST /pay/:id: HTTP %d Location %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	// 顾客只能付款，不能停用或查看链接的支付
+	_, err = client.New(srv.URL).DeactivatePaymentLink(ctx, paymentLink.ID)
+	expectStatus("DeactivatePaymentLink without token", err, 401)
+	_, err = client.New(srv.URL).ListPaymentLinkPayments(ctx, paymentLink.ID)
+	expectStatus("ListPaymentLinkPayments without token", err, 401)
+	_, err = c.DeactivatePaymentLink(ctx, paymentLink.ID)
+	must("DeactivatePaymentLink", err)
+
+	// 商户webhook投递
+	deliveries, err := c.ListWebhookDeliveries(ctx, client.ListWebhookDeliveriesParams{EndpointID: endpoint.ID})
+	must("ListWebhookDeliveries", err)
+	if len(deliveries) == 0 {
+		t.Fatal("支付事件未生成webhook投递记录")
+	}
+	_, err = c.GetWebhookDelivery(ctx, deliveries[0].ID)
+	must("GetWebhookDelivery", err)
+	_, err = c.RedeliverWebhook(ctx, deliveries[0].ID)
+	must("RedeliverWebhook", err)
+
+	// 入站webhook（只供Evonet回调，客户端不生成方法）创建拒付
+	notification := fmt.Sprintf(`{"eventCode":"DISPUTE_CREATED","dispute":{"disputeId":"dp-contract-1","merchantTransId":%q,"amount":200,"currency":"JPY","reason":"fraud","evidenceDueBy":%q},"timestamp":%q}`,
+		direct.MerchantTransID, time.Now().Add(72*time.Hour).Format(time.RFC3339), time.Now().Format(time.RFC3339))
+	postWebhook := func(auth, body string) int {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/payment/webhook", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", auth)
+		resp, err := http.DefaultClient.Do(req)
+		must("ReceiveWebhook", err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	signKey := config.Load().GetCurrentEvonetConfig().SignKey
+	if status := postWebhook(signKey, notification); status != 200 {
+		t.Fatalf("ReceiveWebhook: HTTP %d", status)
+	}
+	if status := postWebhook(signKey, "not json"); status != 400 {
+		t.Errorf("ReceiveWebhook invalid: 期望HTTP 400，得到 %d", status)
+	}
+
+	events, err := c.ListWebhookEvents(ctx, client.ListWebhookEventsParams{MerchantTransID: direct.MerchantTransID})
+	must("ListWebhookEvents", err)
+	if len(events) == 0 {
+		t.Fatal("入站webhook未落库")
+	}
+	_, err = c.GetWebhookEvent(ctx, events[0].ID)
+	must("GetWebhookEvent", err)
+	_, err = c.ReplayWebhookEvent(ctx, events[0].ID)
+	must("ReplayWebhookEvent", err)
+
+	// 拒付
+	_, err = c.ListDisputes(ctx, client.ListDisputesParams{})
+	must("ListDisputes", err)
+	_, err = c.GetDispute(ctx, "dp-contract-1")
+	must("GetDispute", err)
+	_, err = c.GetDispute(ctx, "dp-missing")
+	expectStatus("GetDispute missing", err, 404)
+	evidence, err := c.UploadDisputeEvidence(ctx, "dp-contract-1", &client.UploadDisputeEvidenceForm{
+		File:        strings.NewReader("shipping confirmation"),
+		FileName:    "shipping.txt",
+		Description: "物流单",
+	})
+	must("UploadDisputeEvidence", err)
+	must("DownloadDisputeEvidence", c.DownloadDisputeEvidence(ctx, io.Discard, "dp-contract-1", evidence.ID))
+	_, err = c.RespondDispute(ctx, "dp-contract-1", &client.DisputeResponseRequest{Action: "challenge"})
+	must("RespondDispute", err)
+
+	// 报表与对账
+	must("ExportTransactionReport", c.ExportTransactionReport(ctx, io.Discard, client.ExportTransactionReportParams{Format: "csv"}))
+	settlement := "merchantTransID,amount,currency\n" + direct.MerchantTransID + ",1000,JPY\nunknown-trans,50,JPY\n"
+	recon, err := c.CreateReconciliation(ctx, client.CreateReconciliationParams{FileName: "settlement.csv"}, strings.NewReader(settlement))
+	must("CreateReconciliation", err)
+	_, err = c.ListReconciliations(ctx)
+	must("ListReconciliations", err)
+	_, err = c.GetReconciliation(ctx, recon.ID, client.GetReconciliationParams{})
+	must("GetReconciliation", err)
+
+	// 风控与费率
+	assessments, err := c.ListRiskAssessments(ctx, client.ListRiskAssessmentsParams{})
+	must("ListRiskAssessments", err)
+	if len(assessments) == 0 {
+		t.Fatal("卡支付未生成风控评估")
+	}
+	_, err = c.GetRiskAssessment(ctx, assessments[0].ID)
+	must("GetRiskAssessment", err)
+	_, err = c.GetRiskRules(ctx)
+	must("GetRiskRules", err)
+	_, err = c.GetFeeSchedule(ctx)
+	must("GetFeeSchedule", err)
+
+	// 付款
+	beneficiary, err := c.CreateBeneficiary(ctx, &client.BeneficiaryRequest{
+		Name:     "Test Supplier",
+		Type:     "individual",
+		Country:  "JP",
+		Currency: "JPY",
+		BankAccount: client.BankAccount{
+			AccountNumber: "12345678",
+			BankName:      "Test Bank",
+			AccountName:   "Test Supplier",
+		},
+	})
+	must("CreateBeneficiary", err)
+	_, err = c.ListBeneficiaries(ctx, client.ListBeneficiariesParams{})
+	must("ListBeneficiaries", err)
+	_, err = c.GetBeneficiary(ctx, beneficiary.ID)
+	must("GetBeneficiary", err)
+	payout, err := c.CreatePayout(ctx, &client.PayoutRequest{BeneficiaryID: beneficiary.ID, Amount: 100, Currency: "JPY"})
+	must("CreatePayout", err)
+	_, err = c.ListPayouts(ctx, client.ListPayoutsParams{})
+	must("ListPayouts", err)
+	_, err = c.GetPayout(ctx, payout.ID)
+	must("GetPayout", err)
+	_, err = c.GetBalances(ctx)
+	must("GetBalances", err)
+	_, err = c.DisableBeneficiary(ctx, beneficiary.ID)
+	must("DisableBeneficiary", err)
+
+	// 账本
+	_, err = c.ListLedgerEntries(ctx, client.ListLedgerEntriesParams{})
+	must("ListLedgerEntries", err)
+	_, err = c.GetLedgerBalances(ctx, client.GetLedgerBalancesParams{})
+	must("GetLedgerBalances", err)
+	_, err = c.VerifyLedger(ctx)
+	must("VerifyLedger", err)
+	_, err = c.BackfillLedger(ctx)
+	must("BackfillLedger", err)
+
+	// 数据文件加载失败：写入后仍保持未就绪，直到确认
+	corrupt := filepath.Join(config.Load().DataDir, "contract_corrupt.json")
+	must("write corrupt data file", os.WriteFile(corrupt, []byte("{"), 0o600))
+	must("write to quarantined collection", store.Open[map[string]string]("contract_corrupt").Put("k", map[string]string{"v": "1"}))
+	if store.Ping() == nil {
+		t.Error("store.Ping: 加载失败的集合写入后不应恢复就绪")
+	}
+	loadErrors, err := c.ListStoreLoadErrors(ctx)
+	must("ListStoreLoadErrors", err)
+	if len(loadErrors) != 1 || loadErrors[0].Collection != "contract_corrupt" || loadErrors[0].QuarantinedTo == "" {
+		t.Fatalf("ListStoreLoadErrors: %+v", loadErrors)
+	}
+	_, err = c.AcknowledgeStoreLoadError(ctx, "contract_corrupt")
+	must("AcknowledgeStoreLoadError", err)
+	_, err = c.AcknowledgeStoreLoadError(ctx, "contract_corrupt")
+	expectStatus("AcknowledgeStoreLoadError again", err, 404)
+	must("store.Ping after acknowledge", store.Ping())
+
+	// 审计
+	_, err = c.ListAuditEntries(ctx, client.ListAuditEntriesParams{Limit: 20})
+	must("ListAuditEntries", err)
+	must("ExportAuditLog", c.ExportAuditLog(ctx, io.Discard, client.ExportAuditLogParams{Format: "csv"}))
+	_, err = c.VerifyAuditLog(ctx)
+	must("VerifyAuditLog", err)
+
+	must("DeleteWebhookEndpoint", c.DeleteWebhookEndpoint(ctx, endpoint.ID))
+
+	for _, problem := range contract.report() {
+		t.Error(problem)
+	}
+}
+
+// contractRecorder 检查每个响应是否符合接口定义，并记录已覆盖的operation
+type contractRecorder struct {
+	spec map[string]interface{}
+
+	mu       sync.Mutex
+	covered  map[string]bool
+	problems []string
+}
+
+func newContractRecorder(spec map[string]interface{}) *contractRecorder {
+	return &contractRecorder{spec: spec, covered: map[string]bool{}}
+}
+
+// bodyRecorder 在写出响应的同时保留一份副本
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (rec *contractRecorder) middleware(c *gin.Context) {
+	w := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+
+	route := c.FullPath()
+	if !strings.HasPrefix(route, "/api/v1") {
+		return
+	}
+	rec.check(c.Request.Method, ginPathToOpenAPI(route), w.Status(), w.Header().Get("Content-Type"), w.body.Bytes())
+}
+
+func (rec *contractRecorder) check(method, path string, status int, contentType string, body []byte) {
+	where := fmt.Sprintf("%s %s -> %d", method, path, status)
+	op := asMap(asMap(asMap(rec.spec["paths"])[path])[strings.ToLower(method)])
+	if op == nil {
+		rec.fail("%s: 接口定义中没有该operation", where)
+		return
+	}
+	responses := asMap(op["responses"])
+	response := asMap(responses[strconv.Itoa(status)])
+	if response == nil {
+		response = asMap(responses["default"])
+	}
+	if response == nil {
+		rec.fail("%s: 状态码未在接口定义中声明", where)
+		return
+	}
+	if status < 300 {
+		rec.mu.Lock()
+		rec.covered[method+" "+path] = true
+		rec.mu.Unlock()
+	}
+
+	response = rec.resolve(response)
+	content := asMap(response["content"])
+	if len(content) == 0 || len(body) == 0 {
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := content[mediaType]
+	if !ok {
+		rec.fail("%s: 响应类型%s未在接口定义中声明", where, mediaType)
+		return
+	}
+	if mediaType != "application/json" {
+		return
+	}
+	schema := asMap(asMap(media)["schema"])
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		rec.fail("%s: 响应不是合法JSON: %v", where, err)
+		return
+	}
+	for _, problem := range rec.validate("$", schema, value) {
+		rec.fail("%s: %s", where, problem)
+	}
+}
+
+// report 返回全部不一致之处，包括未被成功调用过的operation
+func (rec *contractRecorder) report() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	problems := append([]string(nil), rec.problems...)
+	var missing []string
+	for path, item := range asMap(rec.spec["paths"]) {
+		for method := range asMap(item) {
+			key := strings.ToUpper(method) + " " + path
+			if !rec.covered[key] {
+				missing = append(missing, key+": 没有成功调用过，请在契约测试中覆盖")
+			}
+		}
+	}
+	sort.Strings(missing)
+	return append(problems, missing...)
+}
+
+func (rec *contractRecorder) fail(format string, args ...interface{}) {
+	rec.mu.Lock()
+	rec.problems = append(rec.problems, fmt.Sprintf(format, args...))
+	rec.mu.Unlock()
+}
+
+// resolve 展开$ref，并把allOf各分支的属性和必填字段合并为一个schema
+func (rec *contractRecorder) resolve(schema map[string]interface{}) map[string]interface{} {
+	for {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			break
+		}
+		target := interface{}(rec.spec)
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			target = asMap(target)[part]
+		}
+		schema = asMap(target)
+	}
+	allOf, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return schema
+	}
+
+	merged := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	var required []interface{}
+	for _, part := range allOf {
+		for key, value := range rec.resolve(asMap(part)) {
+			switch key {
+			case "properties":
+				for name, prop := range asMap(value) {
+					properties[name] = prop
+				}
+			case "required":
+				required = append(required, value.([]interface{})...)
+			default:
+				merged[key] = value
+			}
+		}
+	}
+	for key, value := range schema {
+		if key != "allOf" {
+			merged[key] = value
+		}
+	}
+	merged["properties"] = properties
+	merged["required"] = required
+	return merged
+}
+
+// validate 校验JSON值，未声明additionalProperties的对象不允许出现文档外的字段
+func (rec *contractRecorder) validate(path string, schema map[string]interface{}, value interface{}) []string {
+	schema = rec.resolve(schema)
+	if len(schema) == 0 {
+		return nil
+	}
+	if value == nil {
+		if schema["nullable"] == true {
+			return nil
+		}
+		return []string{path + ": 值为null，接口定义未声明nullable"}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(enum, value) {
+		return []string{fmt.Sprintf("%s: %v不在枚举%v中", path, value, enum)}
+	}
+
+	typ, _ := schema["type"].(string)
+	if typ == "" && schema["properties"] != nil {
+		typ = "object"
+	}
+	switch typ {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: 期望object，得到%T", path, value)}
+		}
+		return rec.validateObject(path, schema, obj)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: 期望array，得到%T", path, value)}
+		}
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, rec.validate(fmt.Sprintf("%s[%d]", path, i), asMap(schema["items"]), item)...)
+		}
+		return problems
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: 期望string，得到%T", path, value)}
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return []string{fmt.Sprintf("%s: %q不是date-time", path, s)}
+			}
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return []string{fmt.Sprintf("%s: 期望%s，得到%T", path, typ, value)}
+		}
+		if typ == "integer" && n != math.Trunc(n) {
+			return []string{fmt.Sprintf("%s: %v不是整数", path, n)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: 期望boolean，得到%T", path, value)}
+		}
+	}
+	return nil
+}
+
+func (rec *contractRecorder) validateObject(path string, schema map[string]interface{}, obj map[string]interface{}) []string {
+	var problems []string
+	required, _ := schema["required"].([]interface{})
+	for _, name := range required {
+		if _, ok := obj[name.(string)]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: 缺少必填字段%s", path, name))
+		}
+	}
+
+	properties := asMap(schema["properties"])
+	additional, hasAdditional := schema["additionalProperties"]
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := path + "." + name
+		if prop, ok := properties[name]; ok {
+			problems = append(problems, rec.validate(field, asMap(prop), obj[name])...)
+			continue
+		}
+		switch {
+		case !hasAdditional && len(properties) > 0, additional == false:
+			problems = append(problems, field+": 字段未在接口定义中声明")
+		case asMap(additional) != nil:
+			problems = append(problems, rec.validate(field, asMap(additional), obj[name])...)
+		}
+	}
+	return problems
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"payment-demo/config"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// originPattern 允许的来源，host以"*."开头时匹配任意层级子域名，port为"*"时匹配任意端口
+type originPattern struct {
+	scheme   string
+	host     string
+	port     string
+	wildcard bool
+}
+
+// CORS 根据配置创建CORS中间件
+func CORS(cfg *config.Config) gin.HandlerFunc {
+	patterns, allowAll := parseOriginPatterns(cfg.CORSAllowedOrigins)
+
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-Admin-Token", "X-Request-ID"}
+	corsConfig.ExposeHeaders = []string{"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "X-Request-ID"}
+	corsConfig.AllowCredentials = cfg.CORSAllowCredentials
+	corsConfig.MaxAge = cfg.CORSMaxAge
+	if allowAll && corsConfig.AllowCredentials {
+		// 任意来源携带凭证等同于关闭同源保护
+		fmt.Println("[CORS] 允许任意来源时不允许携带凭证，已关闭AllowCredentials")
+		corsConfig.AllowCredentials = false
+	}
+	corsConfig.AllowOriginFunc = func(origin string) bool {
+		return allowAll || matchOrigin(patterns, origin)
+	}
+
+	fmt.Printf("[CORS] 允许的来源: %s\n", strings.Join(cfg.CORSAllowedOrigins, ", "))
+	return cors.New(corsConfig)
+}
+
+// parseOriginPatterns 解析来源列表，"*"表示允许任意来源；无效项记录日志后忽略
+func parseOriginPatterns(origins []string) ([]originPattern, bool) {
+	var patterns []originPattern
+	allowAll := false
+	for _, origin := range origins {
+		if origin == "*" {
+			allowAll = true
+			continue
+		}
+		p, ok := parseOrigin(origin)
+		if !ok {
+			fmt.Printf("[CORS] 忽略无效的来源配置: %q\n", origin)
+			continue
+		}
+		if strings.HasPrefix(p.host, "*.") {
+			p.wildcard = true
+			p.host = p.host[2:]
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, allowAll
+}
+
+// parseOrigin 拆分 scheme://host[:port]，忽略路径
+func parseOrigin(origin string) (originPattern, bool) {
+	scheme, rest, ok := strings.Cut(strings.ToLower(strings.TrimSpace(origin)), "://")
+	if !ok || (scheme != "http" && scheme != "https") {
+		return originPattern{}, false
+	}
+	hostport, _, _ := strings.Cut(rest, "/")
+	host, port, _ := strings.Cut(hostport, ":")
+	if host == "" || (strings.Contains(host, "*") && (!strings.HasPrefix(host, "*.") || strings.Contains(host[2:], "*"))) {
+		return originPattern{}, false
+	}
+	if port == defaultPort(scheme) {
+		port = ""
+	}
+	return originPattern{scheme: scheme, host: host, port: port}, true
+}
+
+func defaultPort(scheme string) string {
+	if scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// matchOrigin 判断请求的Origin是否匹配任一允许的来源
+func matchOrigin(patterns []originPattern, origin string) bool {
+	o, ok := parseOrigin(origin)
+	if !ok || strings.Contains(o.host, "*") || o.port == "*" {
+		return false
+	}
+	for _, p := range patterns {
+		if p.scheme != o.scheme {
+			continue
+		}
+		if p.port != "*" && p.port != o.port {
+			continue
+		}
+		if p.wildcard {
+			if strings.HasSuffix(o.host, "."+p.host) {
+				return true
+			}
+			continue
+		}
+		if p.host == o.host {
+			return true
+		}
+	}
+	return false
+}
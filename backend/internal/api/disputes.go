@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"payment-demo/config"
+	"payment-demo/internal/audit"
+	"payment-demo/internal/dispute"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 获取拒付列表，按创建时间倒序
+func listDisputes(c *gin.Context) {
+	status := c.Query("status")
+	merchantTransID := c.Query("merchantTransId")
+
+	disputes := dispute.Disputes().Filter(func(d models.Dispute) bool {
+		return (status == "" || d.Status == status) &&
+			(merchantTransID == "" || d.MerchantTransID == merchantTransID)
+	})
+	sort.Slice(disputes, func(i, j int) bool {
+		return disputes[i].CreatedAt.After(disputes[j].CreatedAt)
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    disputes,
+	})
+}
+
+// 查询拒付详情
+func getDispute(c *gin.Context) {
+	d, ok := dispute.Disputes().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get dispute")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    d,
+	})
+}
+
+// 上传拒付证据，multipart表单字段file（PDF/图片/文本）和可选的description
+func uploadDisputeEvidence(c *gin.Context) {
+	// 为multipart的边界和其他字段预留1MB
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.Load().DisputeEvidenceMaxBytes+1<<20)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		status := 400
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = 413
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Invalid evidence upload",
+			"error":   err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	id := c.Param("id")
+	evidence, err := dispute.AddEvidence(id, header.Filename, c.PostForm("description"), file)
+	if err != nil {
+		respondDisputeError(c, err, "Failed to upload evidence")
+		return
+	}
+	recordAudit(c, audit.ActionDisputeEvidence, "dispute", id, nil, evidence)
+
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    evidence,
+	})
+}
+
+// 下载拒付证据
+func downloadDisputeEvidence(c *gin.Context) {
+	id := c.Param("id")
+	evidence, err := dispute.FindEvidence(id, c.Param("evidenceId"))
+	if err != nil {
+		respondStoreError(c, err, "Failed to get evidence")
+		return
+	}
+
+	c.Header("Content-Type", evidence.ContentType)
+	c.FileAttachment(dispute.EvidencePath(id, evidence.ID), evidence.FileName)
+}
+
+// 接受拒付或提交证据应诉
+func respondDispute(c *gin.Context) {
+	var req models.DisputeResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	id := c.Param("id")
+	before, after, err := dispute.Respond(id, &req)
+	if err != nil {
+		respondDisputeError(c, err, "Failed to respond to dispute")
+		return
+	}
+	recordAudit(c, audit.ActionDisputeRespond, "dispute", id, before, after)
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    after,
+	})
+}
+
+// respondDisputeError 将拒付相关错误映射为HTTP状态码
+func respondDisputeError(c *gin.Context, err error, message string) {
+	status := 500
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		status = 404
+	case errors.Is(err, dispute.ErrInvalidEvidence):
+		status = 400
+	case errors.Is(err, dispute.ErrNotRespondable), errors.Is(err, dispute.ErrEvidenceRequired):
+		status = 409
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"message": message,
+		"error":   err.Error(),
+	})
+}
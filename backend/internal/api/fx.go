@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/fx"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 获取当前汇率表
+func getFXRates(c *gin.Context) {
+	rates, err := fx.CurrentRates()
+	if err != nil {
+		c.JSON(503, gin.H{
+			"success": false,
+			"message": "Exchange rates are not available",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    rates,
+	})
+}
+
+// 创建汇率报价：将基础币种金额换算为支付币种并锁定到expiresAt
+func createFXQuote(c *gin.Context) {
+	var req models.FXQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	quote, err := fx.CreateQuote(&req)
+	if err != nil {
+		status := 500
+		switch {
+		case errors.Is(err, fx.ErrNoRate),
+			errors.Is(err, catalog.ErrUnsupportedCurrency),
+			errors.Is(err, catalog.ErrAmountOutOfRange):
+			status = 400
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to create FX quote",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    quote,
+	})
+}
+
+// 查询汇率报价
+func getFXQuote(c *gin.Context) {
+	quote, ok := fx.GetQuote(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get FX quote")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    quote,
+	})
+}
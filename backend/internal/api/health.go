@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/audit"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/health"
+	"payment-demo/internal/service"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthCheckTimeout 单次健康检查的总超时
+const healthCheckTimeout = 5 * time.Second
+
+var registerHealthChecksOnce sync.Once
+
+// registerHealthChecks 注册就绪检查的各个组件
+// Evonet不可达或熔断时实例本身无法恢复，只标记为degraded而不摘除流量
+func registerHealthChecks() {
+	registerHealthChecksOnce.Do(func() {
+		health.Register("store", true, func(context.Context) error {
+			return store.Ping()
+		})
+		health.Register("config", true, func(context.Context) error {
+			cfg := config.Load()
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			if !cfg.HasAPIKeys() {
+				return errors.New("API keys missing for current environment")
+			}
+			if catalog.Current() == nil {
+				return errors.New("catalog not loaded")
+			}
+			return nil
+		})
+		health.Register("evonet", false, service.ProbeEvonet)
+		health.RegisterWithDetails("evonet_breaker", false, func(context.Context) error {
+			return service.CheckEvonetBreaker()
+		}, func() interface{} {
+			return service.EvonetBreakers()
+		})
+	})
+}
+
+// 存活检查：后台任务卡死时返回503，由平台重启实例
+func getLiveness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+	respondHealth(c, health.Live(ctx))
+}
+
+// 就绪检查：关键组件异常或停机排空期间返回503
+func getReadiness(c *gin.Context) {
+	if health.Draining() {
+		c.JSON(503, gin.H{"status": health.StatusDraining})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+	respondHealth(c, health.Ready(ctx))
+}
+
+func respondHealth(c *gin.Context, report health.Report) {
+	status := 200
+	if !report.Healthy() {
+		status = 503
+	}
+	c.JSON(status, report)
+}
+
+// 查询尚未确认的数据文件加载失败
+func listStoreLoadErrors(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    store.LoadErrors(),
+	})
+}
+
+// 确认数据文件加载失败：运维已从隔离文件恢复数据或确认放弃后调用，之后store恢复就绪
+func acknowledgeStoreLoadError(c *gin.Context) {
+	loadErr, err := store.AcknowledgeLoadError(c.Param("collection"))
+	if err != nil {
+		respondStoreError(c, err, "No load error for collection")
+		return
+	}
+	recordAudit(c, audit.ActionStoreLoadErrorAck, "collection", loadErr.Collection, loadErr, nil)
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "Load error acknowledged",
+		"data":    loadErr,
+	})
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"payment-demo/config"
+	"payment-demo/internal/dispute"
+	"payment-demo/internal/ledger"
+	"payment-demo/internal/payout"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 查询日记账分录，按记账时间排序
+func listLedgerEntries(c *gin.Context) {
+	q, err := ledgerQuery(c)
+	if err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+	q.SourceID = c.Query("sourceId")
+	if q.From, err = parseTimeParam(c.Query("from"), false); err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid query parameters", "error": "from: " + err.Error()})
+		return
+	}
+	if q.To, err = parseTimeParam(c.Query("to"), true); err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid query parameters", "error": "to: " + err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    ledger.List(q),
+	})
+}
+
+// 查询账户余额；at为时点（日期表示当天结束时），默认当前；environment默认当前环境
+func getLedgerBalances(c *gin.Context) {
+	q, err := ledgerQuery(c)
+	if err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid query parameters", "error": err.Error()})
+		return
+	}
+	if q.Environment == "" {
+		q.Environment = string(config.Load().GetCurrentAPIEnv())
+	}
+	at, err := parseTimeParam(c.Query("at"), true)
+	if err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid query parameters", "error": "at: " + err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    ledger.Balances(q, at),
+	})
+}
+
+// 校验全部分录借贷平衡、各币种合计为零，且与扣款、退款、拒付和付款记录一致
+func verifyLedger(c *gin.Context) {
+	result := ledger.Verify(ledger.Expected(payout.Payouts().List(), dispute.Disputes().List()))
+	status := 200
+	if !result.Valid {
+		status = 409
+	}
+	c.JSON(status, gin.H{
+		"success": result.Valid,
+		"data":    result,
+	})
+}
+
+// 为启用记账前或记账失败的扣款、退款、拒付和付款补记分录，可重复执行
+func backfillLedger(c *gin.Context) {
+	posted, err := ledger.Backfill(payout.Payouts().List(), dispute.Disputes().List())
+	if err != nil {
+		c.JSON(500, gin.H{
+			"success": false,
+			"message": "Failed to backfill ledger",
+			"error":   err.Error(),
+		})
+		return
+	}
+	fmt.Printf("[Ledger] 补记分录完成 - 新增: %d\n", posted)
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    gin.H{"posted": posted},
+	})
+}
+
+// ledgerQuery 解析 environment、currency、account 过滤条件
+func ledgerQuery(c *gin.Context) (ledger.Query, error) {
+	q := ledger.Query{
+		Environment: c.Query("environment"),
+		Currency:    strings.ToUpper(c.Query("currency")),
+		Account:     c.Query("account"),
+	}
+	if _, ok := ledger.Accounts[q.Account]; q.Account != "" && !ok {
+		return q, fmt.Errorf("unknown account: %s", q.Account)
+	}
+	return q, nil
+}
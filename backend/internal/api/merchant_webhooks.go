@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+
+	"payment-demo/internal/audit"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 注册商户webhook接收地址
+func createWebhookEndpoint(c *gin.Context) {
+	var req struct {
+		URL         string   `json:"url" binding:"required"`
+		Events      []string `json:"events"`
+		Description string   `json:"description"`
+		Secret      string   `json:"secret"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{
+			"success": false,
+			"message": "Invalid request parameters",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	endpoint, err := webhook.CreateEndpoint(req.URL, req.Events, req.Description, req.Secret)
+	if err != nil {
+		status := 500
+		if errors.Is(err, webhook.ErrInvalidEndpoint) {
+			status = 400
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to create webhook endpoint",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	logged := endpoint
+	logged.Secret = ""
+	recordAudit(c, audit.ActionWebhookEndpointAdd, "webhook_endpoint", endpoint.ID, nil, logged)
+
+	// 仅在创建时返回secret
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    endpoint,
+	})
+}
+
+// 获取商户webhook接收地址列表
+func listWebhookEndpoints(c *gin.Context) {
+	endpoints := webhook.Endpoints().List()
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    endpoints,
+	})
+}
+
+// 删除商户webhook接收地址
+func deleteWebhookEndpoint(c *gin.Context) {
+	id := c.Param("id")
+	before, _ := webhook.Endpoints().Get(id)
+	if err := webhook.Endpoints().Delete(id); err != nil {
+		respondStoreError(c, err, "Failed to delete webhook endpoint")
+		return
+	}
+	before.Secret = ""
+	recordAudit(c, audit.ActionWebhookEndpointDel, "webhook_endpoint", id, before, nil)
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "Webhook endpoint deleted",
+	})
+}
+
+// 获取投递记录列表，支持按status和endpointId过滤（status=dead即死信队列）
+func listWebhookDeliveries(c *gin.Context) {
+	status := c.Query("status")
+	endpointID := c.Query("endpointId")
+
+	deliveries := webhook.Deliveries().Filter(func(d models.WebhookDelivery) bool {
+		return (status == "" || d.Status == status) && (endpointID == "" || d.EndpointID == endpointID)
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    deliveries,
+	})
+}
+
+// 获取单条投递记录
+func getWebhookDelivery(c *gin.Context) {
+	delivery, ok := webhook.Deliveries().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get webhook delivery")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    delivery,
+	})
+}
+
+// 重新投递
+func redeliverWebhook(c *gin.Context) {
+	delivery, err := webhook.Redeliver(c.Param("id"))
+	if err != nil {
+		respondStoreError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "Delivery queued",
+		"data":    delivery,
+	})
+}
+
+// respondStoreError 将存储层错误转换为HTTP响应
+func respondStoreError(c *gin.Context, err error, message string) {
+	status := 500
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		status = 404
+	case errors.Is(err, store.ErrDuplicate):
+		status = 409
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"message": message,
+		"error":   err.Error(),
+	})
+}
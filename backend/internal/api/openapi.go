@@ -0,0 +1,228 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"payment-demo/internal/audit"
+	"payment-demo/internal/fees"
+	"payment-demo/internal/ledger"
+	"payment-demo/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var openAPISource []byte
+
+var (
+	openAPIOnce sync.Once
+	openAPISpec map[string]interface{}
+	openAPIJSON []byte
+	openAPIErr  error
+)
+
+// specSchemaModels 与models中结构体一一对应的schema，启动时检查字段是否一致
+var specSchemaModels = map[string]reflect.Type{
+	"Country":                reflect.TypeOf(models.Country{}),
+	"PaymentScenario":        reflect.TypeOf(models.PaymentScenario{}),
+	"CardInfo":               reflect.TypeOf(models.CardInfo{}),
+	"Order":                  reflect.TypeOf(models.Order{}),
+	"OrderItem":              reflect.TypeOf(models.OrderItem{}),
+	"Buyer":                  reflect.TypeOf(models.Buyer{}),
+	"Address":                reflect.TypeOf(models.Address{}),
+	"PaymentRequest":         reflect.TypeOf(models.PaymentRequest{}),
+	"PaymentResponse":        reflect.TypeOf(models.PaymentResponse{}),
+	"ActionInfo":             reflect.TypeOf(models.ActionInfo{}),
+	"Payment":                reflect.TypeOf(models.Payment{}),
+	"PaymentRecord":          reflect.TypeOf(models.PaymentRecord{}),
+	"FXRates":                reflect.TypeOf(models.FXRates{}),
+	"FXQuoteRequest":         reflect.TypeOf(models.FXQuoteRequest{}),
+	"FXQuote":                reflect.TypeOf(models.FXQuote{}),
+	"PaymentLinkRequest":     reflect.TypeOf(models.PaymentLinkRequest{}),
+	"PaymentLink":            reflect.TypeOf(models.PaymentLink{}),
+	"PaymentLinkCheckout":    reflect.TypeOf(models.PaymentLinkCheckout{}),
+	"RefundRequest":          reflect.TypeOf(models.RefundRequest{}),
+	"Refund":                 reflect.TypeOf(models.Refund{}),
+	"Fees":                   reflect.TypeOf(models.Fees{}),
+	"FeeRule":                reflect.TypeOf(fees.Rule{}),
+	"FeeSchedule":            reflect.TypeOf(fees.Schedule{}),
+	"WebhookNotification":    reflect.TypeOf(models.WebhookNotification{}),
+	"WebhookEndpoint":        reflect.TypeOf(models.WebhookEndpoint{}),
+	"WebhookEvent":           reflect.TypeOf(models.WebhookEvent{}),
+	"WebhookDelivery":        reflect.TypeOf(models.WebhookDelivery{}),
+	"WebhookDeliveryAttempt": reflect.TypeOf(models.WebhookDeliveryAttempt{}),
+	"InboundWebhookEvent":    reflect.TypeOf(models.InboundWebhookEvent{}),
+	"ReconciliationItem":     reflect.TypeOf(models.ReconciliationItem{}),
+	"Reconciliation":         reflect.TypeOf(models.Reconciliation{}),
+	"RiskRuleResult":         reflect.TypeOf(models.RiskRuleResult{}),
+	"RiskAssessment":         reflect.TypeOf(models.RiskAssessment{}),
+	"AuditEntry":             reflect.TypeOf(models.AuditEntry{}),
+	"DisputeNotification":    reflect.TypeOf(models.DisputeNotification{}),
+	"Dispute":                reflect.TypeOf(models.Dispute{}),
+	"DisputeEvidence":        reflect.TypeOf(models.DisputeEvidence{}),
+	"DisputeResponseRequest": reflect.TypeOf(models.DisputeResponseRequest{}),
+	"BankAccount":            reflect.TypeOf(models.BankAccount{}),
+	"BeneficiaryRequest":     reflect.TypeOf(models.BeneficiaryRequest{}),
+	"Beneficiary":            reflect.TypeOf(models.Beneficiary{}),
+	"PayoutRequest":          reflect.TypeOf(models.PayoutRequest{}),
+	"Payout":                 reflect.TypeOf(models.Payout{}),
+	"Balance":                reflect.TypeOf(models.Balance{}),
+	"Posting":                reflect.TypeOf(models.Posting{}),
+	"JournalEntry":           reflect.TypeOf(models.JournalEntry{}),
+	"LedgerBalance":          reflect.TypeOf(models.LedgerBalance{}),
+	"LedgerVerification":     reflect.TypeOf(ledger.Verification{}),
+	"AuditVerification":      reflect.TypeOf(audit.Verification{}),
+	"FieldError":             reflect.TypeOf(FieldError{}),
+}
+
+// loadOpenAPI 解析内嵌的YAML接口定义并转换为JSON
+func loadOpenAPI() (map[string]interface{}, []byte, error) {
+	openAPIOnce.Do(func() {
+		if openAPIErr = yaml.Unmarshal(openAPISource, &openAPISpec); openAPIErr != nil {
+			openAPIErr = fmt.Errorf("failed to parse openapi.yaml: %w", openAPIErr)
+			return
+		}
+		openAPIJSON, openAPIErr = json.Marshal(openAPISpec)
+	})
+	return openAPISpec, openAPIJSON, openAPIErr
+}
+
+// 返回OpenAPI 3接口定义
+func getOpenAPISpec(c *gin.Context) {
+	_, data, err := loadOpenAPI()
+	if err != nil {
+		c.JSON(500, gin.H{
+			"success": false,
+			"message": "Failed to load API specification",
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.Data(200, "application/json; charset=utf-8", data)
+}
+
+// Swagger UI页面（静态资源来自CDN）
+func getAPIDocs(c *gin.Context) {
+	c.Data(200, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+  <meta charset="utf-8">
+  <title>Payment Demo API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// CheckAPIContract 检查已注册的/api/v1路由、models结构体与接口定义是否一致
+// 返回所有不一致之处，为空表示一致
+func CheckAPIContract(routes gin.RoutesInfo) []string {
+	spec, _, err := loadOpenAPI()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	documented := make(map[string]bool)
+	paths, _ := spec["paths"].(map[string]interface{})
+	for path, item := range paths {
+		ops, _ := item.(map[string]interface{})
+		for method := range ops {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		key := route.Method + " " + ginPathToOpenAPI(route.Path)
+		registered[key] = true
+		if !documented[key] {
+			problems = append(problems, "route not documented: "+key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			problems = append(problems, "documented route not registered: "+key)
+		}
+	}
+
+	schemas, _ := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for name, typ := range specSchemaModels {
+		schema, ok := schemas[name].(map[string]interface{})
+		if !ok {
+			problems = append(problems, "schema missing: "+name)
+			continue
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		fields := jsonFieldNames(typ)
+		for field := range fields {
+			if _, ok := props[field]; !ok {
+				problems = append(problems, fmt.Sprintf("schema %s missing property %s", name, field))
+			}
+		}
+		for prop := range props {
+			if !fields[prop] {
+				problems = append(problems, fmt.Sprintf("schema %s has unknown property %s", name, prop))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// ginPathToOpenAPI 将 /payment/:id 转换为 /payment/{id}
+func ginPathToOpenAPI(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// jsonFieldNames 返回结构体序列化后的JSON字段名（含内嵌结构体）
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for embedded := range jsonFieldNames(f.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+
+	"payment-demo/internal/audit"
+	"payment-demo/internal/models"
+	"payment-demo/internal/service"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// 创建支付链接
+func createPaymentLink(c *gin.Context) {
+	var req models.PaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	paymentService := service.NewPaymentService()
+	link, err := paymentService.CreatePaymentLink(&req)
+	if err != nil {
+		respondStoreError(c, err, "Failed to create payment link")
+		return
+	}
+	recordAudit(c, audit.ActionPaymentLinkCreate, "payment_link", link.ID, nil, link)
+
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    link,
+	})
+}
+
+// 获取支付链接列表，可按status过滤，按创建时间倒序
+func listPaymentLinks(c *gin.Context) {
+	status := c.Query("status")
+
+	links := service.PaymentLinks().List()
+	filtered := links[:0]
+	for _, link := range links {
+		link = service.WithLinkUsage(link)
+		if status == "" || link.Status == status {
+			filtered = append(filtered, link)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    filtered,
+	})
+}
+
+// 查询支付链接
+func getPaymentLink(c *gin.Context) {
+	link, ok := service.PaymentLinks().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get payment link")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    service.WithLinkUsage(link),
+	})
+}
+
+// 停用支付链接
+func deactivatePaymentLink(c *gin.Context) {
+	id := c.Param("id")
+	before, _ := service.PaymentLinks().Get(id)
+
+	paymentService := service.NewPaymentService()
+	link, err := paymentService.DeactivatePaymentLink(id)
+	if err != nil {
+		respondStoreError(c, err, "Failed to deactivate payment link")
+		return
+	}
+	recordAudit(c, audit.ActionPaymentLinkDeactivate, "payment_link", id, before, link)
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    link,
+	})
+}
+
+// 获取通过支付链接发起的支付
+func listPaymentLinkPayments(c *gin.Context) {
+	id := c.Param("id")
+	if _, ok := service.PaymentLinks().Get(id); !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get payment link")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    service.LinkPayments(id),
+	})
+}
+
+// 通过支付链接发起LinkPay支付，返回Evonet的linkUrl
+func checkoutPaymentLink(c *gin.Context) {
+	var req models.PaymentLinkCheckout
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	response, ok := startPaymentLinkCheckout(c, c.Param("id"), req.Amount)
+	if !ok {
+		return
+	}
+	c.JSON(200, response)
+}
+
+// 支付链接的分享地址：只展示确认页，不创建支付
+// 聊天、邮件的链接预览会直接GET该地址，若在GET时发起支付会占用单次链接
+func showPaymentLinkPage(c *gin.Context) {
+	link, ok := service.PaymentLinks().Get(c.Param("id"))
+	if !ok {
+		c.Data(404, "text/html; charset=utf-8", []byte("<!DOCTYPE html><title>Not found</title><p>Payment link not found</p>"))
+		return
+	}
+	link = service.WithLinkUsage(link)
+
+	status := 200
+	if link.Status != models.PaymentLinkActive {
+		status = 410
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := paymentLinkPage.Execute(c.Writer, gin.H{
+		"Link":   link,
+		"Active": link.Status == models.PaymentLinkActive,
+		"Amount": c.Query("amount"),
+	}); err != nil {
+		fmt.Printf("[PaymentLinks] 渲染支付链接页面失败 - id: %s, error: %v\n", link.ID, err)
+	}
+}
+
+// 支付链接确认页提交：发起支付后重定向到Evonet LinkPay页面，顾客输入金额时通过表单amount传入
+func payPaymentLink(c *gin.Context) {
+	var req models.PaymentLinkCheckout
+	if err := c.ShouldBind(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	response, ok := startPaymentLinkCheckout(c, c.Param("id"), req.Amount)
+	if !ok {
+		return
+	}
+	if response.LinkURL == "" {
+		c.JSON(502, gin.H{
+			"success": false,
+			"message": "Evonet did not return a payment page",
+			"error":   response.Message,
+		})
+		return
+	}
+	c.Redirect(303, response.LinkURL)
+}
+
+var paymentLinkPage = template.Must(template.New("payment-link").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="robots" content="noindex">
+  <title>{{if .Link.Description}}{{.Link.Description}}{{else}}Payment{{end}}</title>
+</head>
+<body>
+  <h1>{{if .Link.Description}}{{.Link.Description}}{{else}}Payment{{end}}</h1>
+  {{if .Active}}
+  <form method="post" action="/pay/{{.Link.ID}}">
+    {{if .Link.Amount}}
+    <p>{{.Link.Amount}} {{.Link.Currency}}</p>
+    {{else}}
+    <label>Amount ({{.Link.Currency}})
+      <input type="number" name="amount" step="any" min="{{.Link.MinAmount}}"{{if .Link.MaxAmount}} max="{{.Link.MaxAmount}}"{{end}} value="{{.Amount}}" required>
+    </label>
+    {{end}}
+    <button type="submit">Pay</button>
+  </form>
+  {{else}}
+  <p>This payment link is {{.Link.Status}}.</p>
+  {{end}}
+</body>
+</html>
+`))
+
+// startPaymentLinkCheckout 校验金额和链接状态后创建支付，失败时已写入响应
+func startPaymentLinkCheckout(c *gin.Context, id string, amount float64) (*models.PaymentResponse, bool) {
+	link, ok := service.PaymentLinks().Get(id)
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get payment link")
+		return nil, false
+	}
+
+	req, err := service.PaymentLinkRequest(link, amount)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"success": false,
+			"message": "Invalid amount",
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+	// 与直接创建支付使用相同的校验（币种金额范围、支付方式）
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		respondBindError(c, err)
+		return nil, false
+	}
+
+	paymentService := service.NewPaymentService()
+	response, err := paymentService.CreatePaymentLinkInteraction(req)
+	auditPaymentCreate(c, req, err)
+	if err != nil {
+		status := 500
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			status = 404
+		case errors.Is(err, service.ErrPaymentLinkUnavailable):
+			status = 410
+		case errors.Is(err, service.ErrCircuitOpen):
+			status = 503
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to create payment",
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+	return response, true
+}
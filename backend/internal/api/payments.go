@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// 查询支付列表，支持过滤、排序和游标分页
+func listPayments(c *gin.Context) {
+	q, err := parsePaymentQuery(c)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"success": false,
+			"message": "Invalid query parameters",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	page, err := store.QueryPayments(q)
+	if err != nil {
+		status := 500
+		if errors.Is(err, store.ErrInvalidCursor) {
+			status = 400
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to list payments",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    page.Items,
+		"pagination": gin.H{
+			"limit":      q.Limit,
+			"hasMore":    page.HasMore,
+			"nextCursor": page.NextCursor,
+		},
+	})
+}
+
+// parsePaymentQuery 解析列表查询参数
+func parsePaymentQuery(c *gin.Context) (store.PaymentQuery, error) {
+	q := store.PaymentQuery{
+		Status:          c.Query("status"),
+		PaymentType:     c.Query("paymentType"),
+		Currency:        c.Query("currency"),
+		Environment:     c.Query("environment"),
+		MerchantTransID: c.Query("merchantTransId"),
+		Metadata:        c.QueryMap("metadata"),
+		Cursor:          c.Query("cursor"),
+		Limit:           defaultPageLimit,
+	}
+
+	var err error
+	if q.CreatedFrom, err = parseTimeParam(c.Query("createdFrom"), false); err != nil {
+		return q, fmt.Errorf("createdFrom: %w", err)
+	}
+	if q.CreatedTo, err = parseTimeParam(c.Query("createdTo"), true); err != nil {
+		return q, fmt.Errorf("createdTo: %w", err)
+	}
+	if q.MinAmount, err = parseAmountParam(c.Query("minAmount")); err != nil {
+		return q, fmt.Errorf("minAmount: %w", err)
+	}
+	if q.MaxAmount, err = parseAmountParam(c.Query("maxAmount")); err != nil {
+		return q, fmt.Errorf("maxAmount: %w", err)
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return q, errors.New("limit must be a positive integer")
+		}
+		q.Limit = min(n, maxPageLimit)
+	}
+
+	switch sortBy := c.DefaultQuery("sort", store.SortByCreatedAt); sortBy {
+	case store.SortByCreatedAt, store.SortByUpdatedAt, store.SortByAmount:
+		q.SortBy = sortBy
+	default:
+		return q, fmt.Errorf("sort must be one of %s, %s, %s", store.SortByCreatedAt, store.SortByUpdatedAt, store.SortByAmount)
+	}
+
+	switch order := c.DefaultQuery("order", "desc"); order {
+	case "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return q, errors.New("order must be 'asc' or 'desc'")
+	}
+
+	return q, nil
+}
+
+// parseTimeParam 解析RFC3339时间或YYYY-MM-DD日期；作为结束日期时包含当天
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("must be RFC3339 or YYYY-MM-DD")
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func parseAmountParam(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, errors.New("must be a number")
+	}
+	return &amount, nil
+}
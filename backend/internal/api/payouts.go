@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"sort"
+
+	"payment-demo/config"
+	"payment-demo/internal/audit"
+	"payment-demo/internal/models"
+	"payment-demo/internal/payout"
+	"payment-demo/internal/service"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 创建收款人
+func createBeneficiary(c *gin.Context) {
+	var req models.BeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	b, err := payout.CreateBeneficiary(&req)
+	if err != nil {
+		respondStoreError(c, err, "Failed to create beneficiary")
+		return
+	}
+	recordAudit(c, audit.ActionBeneficiaryCreate, "beneficiary", b.ID, nil, b.Masked())
+
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    b.Masked(),
+	})
+}
+
+// 获取收款人列表，按创建时间倒序
+func listBeneficiaries(c *gin.Context) {
+	currency := c.Query("currency")
+	beneficiaries := payout.Beneficiaries().Filter(func(b models.Beneficiary) bool {
+		return currency == "" || b.Currency == currency
+	})
+	sort.Slice(beneficiaries, func(i, j int) bool {
+		return beneficiaries[i].CreatedAt.After(beneficiaries[j].CreatedAt)
+	})
+	for i := range beneficiaries {
+		beneficiaries[i] = beneficiaries[i].Masked()
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    beneficiaries,
+	})
+}
+
+// 查询收款人详情
+func getBeneficiary(c *gin.Context) {
+	b, ok := payout.Beneficiaries().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get beneficiary")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    b.Masked(),
+	})
+}
+
+// 停用收款人
+func disableBeneficiary(c *gin.Context) {
+	id := c.Param("id")
+	b, err := payout.DisableBeneficiary(id)
+	if err != nil {
+		respondStoreError(c, err, "Failed to disable beneficiary")
+		return
+	}
+	recordAudit(c, audit.ActionBeneficiaryDisable, "beneficiary", id, map[string]bool{"active": true}, map[string]bool{"active": false})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    b.Masked(),
+	})
+}
+
+// 创建付款并提交出款，金额不能超过该币种的可付款余额
+func createPayout(c *gin.Context) {
+	var req models.PayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	env := string(config.Load().GetCurrentAPIEnv())
+	p, err := payout.Create(&req, env, service.PayoutProvider())
+	if err != nil {
+		status := 500
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			status = 404
+		case errors.Is(err, payout.ErrCurrencyMismatch):
+			status = 400
+		case errors.Is(err, payout.ErrInsufficientFunds), errors.Is(err, payout.ErrBeneficiaryDisabled):
+			status = 409
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to create payout",
+			"error":   err.Error(),
+		})
+		return
+	}
+	recordAudit(c, audit.ActionPayoutCreate, "payout", p.ID, nil, p)
+
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    p,
+	})
+}
+
+// 获取付款列表，按创建时间倒序
+func listPayouts(c *gin.Context) {
+	status := c.Query("status")
+	beneficiaryID := c.Query("beneficiaryId")
+	currency := c.Query("currency")
+
+	payouts := payout.Payouts().Filter(func(p models.Payout) bool {
+		return (status == "" || p.Status == status) &&
+			(beneficiaryID == "" || p.BeneficiaryID == beneficiaryID) &&
+			(currency == "" || p.Currency == currency)
+	})
+	sort.Slice(payouts, func(i, j int) bool {
+		return payouts[i].CreatedAt.After(payouts[j].CreatedAt)
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    payouts,
+	})
+}
+
+// 查询付款详情
+func getPayout(c *gin.Context) {
+	p, ok := payout.Payouts().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get payout")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    p,
+	})
+}
+
+// 获取当前环境各币种的可付款余额
+func getBalances(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    payout.Balances(string(config.Load().GetCurrentAPIEnv())),
+	})
+}
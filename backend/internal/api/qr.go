@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"payment-demo/config"
+	"payment-demo/internal/models"
+	"payment-demo/internal/qr"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+)
+
+// 生成LinkPay链接的二维码
+// format=png|svg（默认按Accept，否则png），size为像素边长，ec为纠错级别L/M/Q/H，logo=true时叠加QR_LOGO_PATH
+func getPaymentQR(c *gin.Context) {
+	payment, ok := store.Payments().Get(c.Param("merchantTransId"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get payment")
+		return
+	}
+	if payment.LinkURL == "" {
+		c.JSON(404, gin.H{
+			"success": false,
+			"message": "Payment has no LinkPay URL",
+		})
+		return
+	}
+	switch payment.Status {
+	case models.StatusFailed, models.StatusCancelled, models.StatusExpired:
+		c.JSON(410, gin.H{
+			"success": false,
+			"message": "Payment link is no longer usable",
+			"error":   "payment status is " + payment.Status,
+		})
+		return
+	}
+
+	opts, format, err := parseQROptions(c)
+	if err != nil {
+		status := 500
+		if errors.Is(err, qr.ErrInvalidOptions) {
+			status = 400
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Invalid QR code options",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var (
+		data        []byte
+		contentType string
+	)
+	if format == "svg" {
+		data, err = qr.SVG(payment.LinkURL, opts)
+		contentType = "image/svg+xml"
+	} else {
+		data, err = qr.PNG(payment.LinkURL, opts)
+		contentType = "image/png"
+	}
+	if err != nil {
+		c.JSON(500, gin.H{
+			"success": false,
+			"message": "Failed to generate QR code",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(200, contentType, data)
+}
+
+// parseQROptions 解析查询参数；叠加logo且未指定纠错级别时使用H
+func parseQROptions(c *gin.Context) (qr.Options, string, error) {
+	opts := qr.Options{Size: qr.DefaultSize, Level: qrcode.Medium}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" && strings.Contains(c.GetHeader("Accept"), "image/svg+xml") {
+		format = "svg"
+	}
+	if format != "" && format != "png" && format != "svg" {
+		return opts, "", fmt.Errorf("%w: format must be png or svg", qr.ErrInvalidOptions)
+	}
+
+	if size := c.Query("size"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			return opts, "", fmt.Errorf("%w: size must be an integer", qr.ErrInvalidOptions)
+		}
+		opts.Size = n
+	}
+
+	if logo, _ := strconv.ParseBool(c.Query("logo")); logo {
+		path := config.Load().QRLogoPath
+		if path == "" {
+			return opts, "", fmt.Errorf("%w: no logo is configured (QR_LOGO_PATH)", qr.ErrInvalidOptions)
+		}
+		img, err := qr.LoadLogo(path)
+		if err != nil {
+			return opts, "", err
+		}
+		opts.Logo = img
+		opts.Level = qrcode.Highest
+	}
+
+	if ec := c.Query("ec"); ec != "" {
+		level, err := qr.ParseLevel(ec)
+		if err != nil {
+			return opts, "", err
+		}
+		opts.Level = level
+	}
+	return opts, format, opts.Validate()
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitStore 限流计数存储，多实例部署时可替换为共享实现
+var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+
+// rateLimits 各类接口的限流中间件
+type rateLimits struct {
+	payment gin.HandlerFunc
+	status  gin.HandlerFunc
+	webhook gin.HandlerFunc
+}
+
+// newRateLimits 根据配置创建限流中间件，配置无效时使用默认值
+func newRateLimits(cfg *config.Config) rateLimits {
+	return rateLimits{
+		payment: rateLimit(cfg, "payment", cfg.RateLimitPayment, "10/1m"),
+		status:  rateLimit(cfg, "status", cfg.RateLimitStatus, "120/1m"),
+		webhook: rateLimit(cfg, "webhook", cfg.RateLimitWebhook, "600/1m"),
+	}
+}
+
+// rateLimit 按客户端对一类接口限流，超限返回429并带Retry-After
+func rateLimit(cfg *config.Config, name, value, fallback string) gin.HandlerFunc {
+	if !cfg.RateLimitEnabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		fmt.Printf("[RateLimit] %s 配置无效，使用默认值 %s: %v\n", name, fallback, err)
+		limit, _ = ratelimit.ParseLimit(fallback)
+	}
+	policy := fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Period.Seconds()))
+
+	return func(c *gin.Context) {
+		key := name + ":" + rateLimitClient(c, cfg.RateLimitAPIKeys)
+		result, err := rateLimitStore.Take(key, limit, time.Now())
+		if err != nil {
+			// 存储不可用时放行，避免限流组件故障导致支付不可用
+			fmt.Printf("[RateLimit] 限流存储异常，已放行 - key: %s, error: %v\n", key, err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.ResetAfter))
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", retryAfter)
+			c.AbortWithStatusJSON(429, gin.H{
+				"success": false,
+				"message": "Too many requests",
+				"error":   "rate limit exceeded, retry after " + retryAfter + "s",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitClient 已登记的API Key单独计数，其他请求按客户端IP计数
+// 未登记的X-API-Key不作为标识，避免通过更换请求头绕过限流
+func rateLimitClient(c *gin.Context, apiKeys []string) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		for _, known := range apiKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(known)) == 1 {
+				sum := sha256.Sum256([]byte(key))
+				return "key:" + hex.EncodeToString(sum[:8])
+			}
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"payment-demo/internal/models"
+	"payment-demo/internal/reconcile"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 上传Evonet结算文件并执行对账
+// 支持multipart表单字段file，或直接以CSV作为请求体
+func createReconciliation(c *gin.Context) {
+	var (
+		reader   io.Reader = c.Request.Body
+		fileName           = c.Query("fileName")
+	)
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		reader = file
+		fileName = header.Filename
+	}
+
+	var from, to time.Time
+	var err error
+	if from, err = parseTimeParam(c.Query("from"), false); err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid from", "error": err.Error()})
+		return
+	}
+	if to, err = parseTimeParam(c.Query("to"), true); err != nil {
+		c.JSON(400, gin.H{"success": false, "message": "Invalid to", "error": err.Error()})
+		return
+	}
+
+	lines, err := reconcile.ParseSettlementFile(reader)
+	if err != nil {
+		status := 500
+		if errors.Is(err, reconcile.ErrInvalidFile) {
+			status = 400
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to parse settlement file",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	result, err := reconcile.Run(fileName, lines, from, to)
+	if err != nil {
+		c.JSON(500, gin.H{
+			"success": false,
+			"message": "Failed to reconcile",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(201, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// 获取对账记录列表（仅汇总，不含明细），按时间倒序
+func listReconciliations(c *gin.Context) {
+	results := reconcile.Results().List()
+	for i := range results {
+		results[i].Items = nil
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    results,
+	})
+}
+
+// 获取对账详情，可用category过滤明细
+func getReconciliation(c *gin.Context) {
+	result, ok := reconcile.Results().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get reconciliation")
+		return
+	}
+
+	if category := c.Query("category"); category != "" {
+		items := make([]models.ReconciliationItem, 0, len(result.Items))
+		for _, item := range result.Items {
+			if item.Category == category {
+				items = append(items, item)
+			}
+		}
+		result.Items = items
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
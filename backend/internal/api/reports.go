@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"payment-demo/internal/report"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 导出交易报表，默认导出前一天的CSV
+func exportTransactionReport(c *gin.Context) {
+	format := c.DefaultQuery("format", report.FormatCSV)
+	if !report.IsValidFormat(format) {
+		c.JSON(400, gin.H{
+			"success": false,
+			"message": "format must be one of csv, xlsx, jsonl",
+		})
+		return
+	}
+
+	from, to := report.DayRange(time.Now().AddDate(0, 0, -1))
+	var err error
+	if value := c.Query("from"); value != "" {
+		if from, err = parseTimeParam(value, false); err != nil {
+			c.JSON(400, gin.H{"success": false, "message": "Invalid from", "error": err.Error()})
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = parseTimeParam(value, true); err != nil {
+			c.JSON(400, gin.H{"success": false, "message": "Invalid to", "error": err.Error()})
+			return
+		}
+	}
+	if !from.Before(to) {
+		c.JSON(400, gin.H{"success": false, "message": "from must be before to"})
+		return
+	}
+
+	r := report.Build(from, to)
+	filename := fmt.Sprintf("transactions_%s_%s.%s", from.Format("20060102"), to.Format("20060102"), format)
+	c.Header("Content-Type", report.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(200)
+	if err := r.Write(c.Writer, format); err != nil {
+		fmt.Printf("[Report] 导出报表失败: %v\n", err)
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"sort"
+
+	"payment-demo/internal/fees"
+	"payment-demo/internal/fraud"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func listRiskAssessments(c *gin.Context) {
+	decision := c.Query("decision")
+	merchantTransID := c.Query("merchantTransId")
+	fingerprint := c.Query("cardFingerprint")
+
+	assessments := fraud.Assessments().Filter(func(a models.RiskAssessment) bool {
+		return (decision == "" || a.Decision == decision) &&
+			(merchantTransID == "" || a.MerchantTransID == merchantTransID) &&
+			(fingerprint == "" || a.CardFingerprint == fingerprint)
+	})
+	sort.Slice(assessments, func(i, j int) bool {
+		return assessments[i].CreatedAt.After(assessments[j].CreatedAt)
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    assessments,
+	})
+}
+
+func getRiskAssessment(c *gin.Context) {
+	assessment, ok := fraud.Assessments().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get risk assessment")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    assessment,
+	})
+}
+
+func getRiskRules(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    fraud.Default().Rules(),
+	})
+}
+
+// 获取当前生效的手续费率表
+func getFeeSchedule(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    fees.Default(),
+	})
+}
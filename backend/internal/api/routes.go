@@ -1,20 +1,44 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+
 	"payment-demo/config"
+	"payment-demo/internal/audit"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/fraud"
+	"payment-demo/internal/fx"
 	"payment-demo/internal/models"
 	"payment-demo/internal/service"
+	"payment-demo/internal/store"
 
 	"github.com/gin-gonic/gin"
 )
 
 // 设置所有路由
 func SetupRoutes(r *gin.Engine) {
+	registerValidators()
+	r.Use(requestID)
+	registerHealthChecks()
+	limits := newRateLimits(config.Load())
+
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	r.GET("/health/live", getLiveness)
+	r.GET("/health/ready", getReadiness)
+
+	// 接口文档
+	r.GET("/openapi.json", getOpenAPISpec)
+	r.GET("/docs", getAPIDocs)
+
+	// 支付链接的分享地址：GET只展示确认页，顾客提交后才发起支付
+	r.GET("/pay/:id", limits.status, showPaymentLinkPage)
+	r.POST("/pay/:id", limits.payment, payPaymentLink)
+
 	// API v1 路由组
 	v1 := r.Group("/api/v1")
 	{
@@ -22,73 +46,121 @@ func SetupRoutes(r *gin.Engine) {
 		v1.GET("/countries", getCountries)
 		v1.GET("/scenarios", getScenarios)
 		v1.GET("/config", getConfig)
-		v1.POST("/config/switch-env", switchAPIEnvironment)
+		v1.POST("/config/switch-env", requireAdmin, switchAPIEnvironment)
+
+		// 支付列表查询（跨支付返回订单、买家和元数据，需要管理令牌）
+		v1.GET("/payments", limits.status, requireAdmin, listPayments)
 
 		// 支付相关
 		payment := v1.Group("/payment")
 		{
-			payment.POST("/interaction", createInteraction)
-			payment.POST("/direct", createDirectPayment)
-			payment.POST("/webhook", handleWebhook)
-			payment.GET("/:merchantTransId", getPaymentStatus)
+			payment.POST("/interaction", limits.payment, createInteraction)
+			payment.POST("/direct", limits.payment, createDirectPayment)
+			payment.POST("/webhook", limits.webhook, handleWebhook)
+			payment.GET("/:merchantTransId", limits.status, getPaymentStatus)
+			payment.POST("/:merchantTransId/refund", limits.payment, requireAdmin, refundPayment)
+			payment.GET("/:merchantTransId/qr", limits.status, getPaymentQR)
+		}
+
+		// 汇率与锁定报价
+		fxGroup := v1.Group("/fx")
+		{
+			fxGroup.GET("/rates", limits.status, getFXRates)
+			fxGroup.POST("/quotes", limits.payment, createFXQuote)
+			fxGroup.GET("/quotes/:id", limits.status, getFXQuote)
+		}
+
+		// 支付链接：顾客只能通过checkout（及/pay/:id）付款，管理接口返回订单和买家信息，需要管理令牌
+		links := v1.Group("/payment-links")
+		{
+			links.POST("/:id/checkout", limits.payment, checkoutPaymentLink)
+
+			manage := links.Group("", requireAdmin)
+			manage.POST("", limits.payment, createPaymentLink)
+			manage.GET("", limits.status, listPaymentLinks)
+			manage.GET("/:id", limits.status, getPaymentLink)
+			manage.POST("/:id/deactivate", limits.payment, deactivatePaymentLink)
+			manage.GET("/:id/payments", limits.status, listPaymentLinkPayments)
 		}
 
 		// 交互状态查询（用于LinkPay和Drop-in）
 		interaction := v1.Group("/interaction")
 		{
-			interaction.GET("/:merchantOrderId", getInteractionStatus)
+			interaction.GET("/:merchantOrderId", limits.status, getInteractionStatus)
+		}
+
+		// 商户webhook订阅与投递（地址和签名密钥由平台管理，需要管理令牌）
+		endpoints := v1.Group("/webhook-endpoints", requireAdmin)
+		{
+			endpoints.POST("", createWebhookEndpoint)
+			endpoints.GET("", listWebhookEndpoints)
+			endpoints.DELETE("/:id", deleteWebhookEndpoint)
+		}
+		deliveries := v1.Group("/webhook-deliveries", requireAdmin)
+		{
+			deliveries.GET("", listWebhookDeliveries)
+			deliveries.GET("/:id", getWebhookDelivery)
+			deliveries.POST("/:id/redeliver", redeliverWebhook)
+		}
+
+		// 拒付（应诉、证据涉及商户资金和资料，需要管理令牌）
+		disputes := v1.Group("/disputes", requireAdmin)
+		{
+			disputes.GET("", limits.status, listDisputes)
+			disputes.GET("/:id", limits.status, getDispute)
+			disputes.POST("/:id/evidence", limits.payment, uploadDisputeEvidence)
+			disputes.GET("/:id/evidence/:evidenceId", limits.status, downloadDisputeEvidence)
+			disputes.POST("/:id/respond", limits.payment, respondDispute)
+		}
+
+		// 管理接口
+		admin := v1.Group("/admin", requireAdmin)
+		{
+			admin.GET("/webhook-events", listWebhookEvents)
+			admin.GET("/webhook-events/:id", getWebhookEvent)
+			admin.POST("/webhook-events/:id/replay", replayWebhookEvent)
+			admin.GET("/reports/transactions", exportTransactionReport)
+			admin.POST("/reconciliations", createReconciliation)
+			admin.GET("/reconciliations", listReconciliations)
+			admin.GET("/reconciliations/:id", getReconciliation)
+			admin.GET("/risk/assessments", listRiskAssessments)
+			admin.GET("/risk/assessments/:id", getRiskAssessment)
+			admin.GET("/risk/rules", getRiskRules)
+			admin.GET("/fees/schedule", getFeeSchedule)
+			admin.POST("/beneficiaries", createBeneficiary)
+			admin.GET("/beneficiaries", listBeneficiaries)
+			admin.GET("/beneficiaries/:id", getBeneficiary)
+			admin.POST("/beneficiaries/:id/disable", disableBeneficiary)
+			admin.POST("/payouts", createPayout)
+			admin.GET("/payouts", listPayouts)
+			admin.GET("/payouts/:id", getPayout)
+			admin.GET("/balances", getBalances)
+			admin.GET("/ledger/entries", listLedgerEntries)
+			admin.GET("/ledger/balances", getLedgerBalances)
+			admin.GET("/ledger/verify", verifyLedger)
+			admin.POST("/ledger/backfill", backfillLedger)
+			admin.GET("/audit", listAuditEntries)
+			admin.GET("/audit/export", exportAuditEntries)
+			admin.GET("/audit/verify", verifyAuditLog)
+			admin.GET("/store/load-errors", listStoreLoadErrors)
+			admin.POST("/store/load-errors/:collection/acknowledge", acknowledgeStoreLoadError)
 		}
 	}
 }
 
-// 获取支持的国家列表
+// 获取支持的国家列表，可通过lang参数返回本地化名称
 func getCountries(c *gin.Context) {
-	countries := []models.Country{
-		{Code: "GLOBAL", Name: "Global", Currency: "USD", Language: "en"},
-		{Code: "HK", Name: "Hong Kong", Currency: "HKD", Language: "zh-HK"},
-		{Code: "KR", Name: "South Korea", Currency: "KRW", Language: "ko"},
-		{Code: "JP", Name: "Japan", Currency: "JPY", Language: "ja"},
-		{Code: "MY", Name: "Malaysia", Currency: "MYR", Language: "ms"},
-		{Code: "ID", Name: "Indonesia", Currency: "IDR", Language: "id"},
-		{Code: "TH", Name: "Thailand", Currency: "THB", Language: "th"},
-		{Code: "SG", Name: "Singapore", Currency: "SGD", Language: "en"},
-	}
-
 	c.JSON(200, gin.H{
 		"success": true,
-		"data":    countries,
+		"data":    catalog.Current().CountryList(c.Query("lang")),
 	})
 }
 
 // 获取支付场景列表
 func getScenarios(c *gin.Context) {
-	scenarios := []models.PaymentScenario{
-		{
-			ID:          "uat-ecommerce-linkpay",
-			Name:        "UAT-电商-LinkPay Demo",
-			Environment: "UAT",
-			Type:        "linkpay",
-			Description: "UAT环境电商场景LinkPay支付演示",
-		},
-		{
-			ID:          "uat-ecommerce-dropin",
-			Name:        "UAT-电商-Drop-in Demo",
-			Environment: "UAT",
-			Type:        "dropin",
-			Description: "UAT环境电商场景Drop-in支付演示",
-		},
-		{
-			ID:          "uat-ecommerce-directapi",
-			Name:        "UAT-电商-Direct API Demo",
-			Environment: "UAT",
-			Type:        "directapi",
-			Description: "UAT环境电商场景Direct API支付演示",
-		},
-	}
-
 	c.JSON(200, gin.H{
 		"success": true,
-		"data":    scenarios,
+		"data":    catalog.Current().Scenarios,
 	})
 }
 
@@ -96,18 +168,18 @@ func getScenarios(c *gin.Context) {
 func createInteraction(c *gin.Context) {
 	var req models.PaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{
-			"success": false,
-			"message": "Invalid request parameters",
-			"error":   err.Error(),
-		})
+		respondBindError(c, err)
+		return
+	}
+	if !requirePaymentType(c, &req, models.PaymentTypeLinkPay, models.PaymentTypeDropIn) {
 		return
 	}
 
 	paymentService := service.NewPaymentService()
 	response, err := paymentService.CreateInteraction(&req)
+	auditPaymentCreate(c, &req, err)
 	if err != nil {
-		c.JSON(500, gin.H{
+		c.JSON(createPaymentErrorStatus(err), gin.H{
 			"success": false,
 			"message": "Failed to create payment interaction",
 			"error":   err.Error(),
@@ -122,18 +194,29 @@ func createInteraction(c *gin.Context) {
 func createDirectPayment(c *gin.Context) {
 	var req models.PaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{
-			"success": false,
-			"message": "Invalid request parameters",
-			"error":   err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
+	if !requirePaymentType(c, &req, models.PaymentTypeDirectAPI) {
+		return
+	}
+
+	req.ClientIP = c.ClientIP()
 
 	paymentService := service.NewPaymentService()
 	response, err := paymentService.CreateDirectPayment(&req)
+	auditPaymentCreate(c, &req, err)
+	if errors.Is(err, fraud.ErrBlocked) {
+		// 不返回命中的规则，避免被用于试探
+		c.JSON(402, gin.H{
+			"success":         false,
+			"message":         "Payment declined",
+			"merchantTransId": req.MerchantTransID,
+		})
+		return
+	}
 	if err != nil {
-		c.JSON(500, gin.H{
+		c.JSON(createPaymentErrorStatus(err), gin.H{
 			"success": false,
 			"message": "Failed to create direct payment",
 			"error":   err.Error(),
@@ -144,10 +227,29 @@ func createDirectPayment(c *gin.Context) {
 	c.JSON(200, response)
 }
 
+// createPaymentErrorStatus 创建支付失败时的HTTP状态码
+func createPaymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrDuplicateMerchantTransID),
+		errors.Is(err, fx.ErrQuoteUsed),
+		errors.Is(err, fx.ErrQuoteExpired):
+		return 409
+	case errors.Is(err, fx.ErrQuoteMismatch):
+		return 400
+	case errors.Is(err, store.ErrNotFound):
+		return 404
+	case errors.Is(err, service.ErrCircuitOpen):
+		return 503
+	default:
+		return 500
+	}
+}
+
 // 处理Webhook通知
+// 原始请求先落库并去重，随后由后台任务异步处理
 func handleWebhook(c *gin.Context) {
-	var notification models.WebhookNotification
-	if err := c.ShouldBindJSON(&notification); err != nil {
+	body, err := c.GetRawData()
+	if err != nil {
 		c.JSON(400, gin.H{
 			"success": false,
 			"message": "Invalid webhook data",
@@ -155,8 +257,33 @@ func handleWebhook(c *gin.Context) {
 		return
 	}
 
-	// 在实际应用中，这里应该验证webhook签名
-	// 并更新数据库中的支付状态
+	event, duplicate, err := service.RecordWebhookEvent(c.Request.Header, body)
+	if errors.Is(err, service.ErrInvalidWebhookPayload) {
+		c.JSON(400, gin.H{
+			"success": false,
+			"message": "Invalid webhook data",
+		})
+		return
+	}
+	if err != nil {
+		// 未能落库时返回错误，让Evonet稍后重试
+		c.JSON(500, gin.H{
+			"success": false,
+			"message": "Failed to record webhook",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if duplicate {
+		fmt.Printf("[Webhook] 重复通知已忽略 - id: %s, dedupKey: %s\n", event.ID, event.DedupKey)
+	}
+	if !event.SignatureValid && config.Load().WebhookRequireSignature {
+		c.JSON(401, gin.H{
+			"success": false,
+			"message": "Webhook signature verification failed",
+		})
+		return
+	}
 
 	// 返回SUCCESS确认收到通知
 	c.String(200, "SUCCESS")
@@ -176,7 +303,11 @@ func getPaymentStatus(c *gin.Context) {
 	paymentService := service.NewPaymentService()
 	payment, err := paymentService.GetPaymentStatus(merchantTransId)
 	if err != nil {
-		c.JSON(500, gin.H{
+		status := 500
+		if errors.Is(err, service.ErrCircuitOpen) {
+			status = 503
+		}
+		c.JSON(status, gin.H{
 			"success": false,
 			"message": "Failed to get payment status",
 			"error":   err.Error(),
@@ -190,6 +321,47 @@ func getPaymentStatus(c *gin.Context) {
 	})
 }
 
+// 发起退款
+func refundPayment(c *gin.Context) {
+	var req models.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	merchantTransID := c.Param("merchantTransId")
+	before, _ := store.Payments().Get(merchantTransID)
+
+	paymentService := service.NewPaymentService()
+	refund, err := paymentService.RefundPayment(merchantTransID, &req)
+	if err != nil {
+		status := 500
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			status = 404
+		case errors.Is(err, service.ErrRefundNotAllowed),
+			errors.Is(err, service.ErrEnvironmentMismatch):
+			status = 409
+		case errors.Is(err, service.ErrCircuitOpen):
+			status = 503
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": "Failed to refund payment",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	after, _ := store.Payments().Get(merchantTransID)
+	recordAudit(c, audit.ActionRefundCreate, "payment", merchantTransID, before, gin.H{"refund": refund, "payment": after})
+
+	c.JSON(200, gin.H{
+		"success": refund.Status != models.RefundFailed,
+		"data":    refund,
+	})
+}
+
 // 查询交互状态（用于LinkPay和Drop-in）
 func getInteractionStatus(c *gin.Context) {
 	merchantOrderId := c.Param("merchantOrderId")
@@ -204,7 +376,11 @@ func getInteractionStatus(c *gin.Context) {
 	paymentService := service.NewPaymentService()
 	payment, err := paymentService.GetInteractionStatus(merchantOrderId)
 	if err != nil {
-		c.JSON(500, gin.H{
+		status := 500
+		if errors.Is(err, service.ErrCircuitOpen) {
+			status = 503
+		}
+		c.JSON(status, gin.H{
 			"success": false,
 			"message": "Failed to get interaction status",
 			"error":   err.Error(),
@@ -235,7 +411,7 @@ func getConfig(c *gin.Context) {
 	})
 }
 
-// 切换API环境
+// 切换API环境（影响所有请求使用的密钥，需要管理令牌）
 func switchAPIEnvironment(c *gin.Context) {
 	var req struct {
 		Environment string `json:"environment" binding:"required"`
@@ -251,6 +427,7 @@ func switchAPIEnvironment(c *gin.Context) {
 	}
 
 	cfg := config.Load()
+	previousEnv := cfg.GetCurrentAPIEnv()
 	var apiEnv config.APIEnvironment
 
 	switch req.Environment {
@@ -275,6 +452,9 @@ func switchAPIEnvironment(c *gin.Context) {
 		return
 	}
 
+	recordAudit(c, audit.ActionEnvironmentSwitch, "config", "apiEnvironment",
+		gin.H{"environment": previousEnv}, gin.H{"environment": cfg.GetCurrentAPIEnv()})
+
 	// 返回切换后的配置信息
 	currentConfig := cfg.GetCurrentEvonetConfig()
 	c.JSON(200, gin.H{
@@ -287,3 +467,19 @@ func switchAPIEnvironment(c *gin.Context) {
 		},
 	})
 }
+
+// auditPaymentCreate 记录一次创建支付的尝试，成功时附带本地支付记录
+func auditPaymentCreate(c *gin.Context, req *models.PaymentRequest, err error) {
+	after := gin.H{
+		"paymentType": req.PaymentType,
+		"amount":      req.Amount,
+		"currency":    req.Currency,
+	}
+	if record, ok := store.Payments().Get(req.MerchantTransID); ok {
+		after["payment"] = record
+	}
+	if err != nil {
+		after["error"] = err.Error()
+	}
+	recordAudit(c, audit.ActionPaymentCreate, "payment", req.MerchantTransID, nil, after)
+}
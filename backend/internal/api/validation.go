@@ -0,0 +1,280 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var registerValidatorsOnce sync.Once
+
+// metadataKeyPattern metadata键只允许字母、数字和 _ - .，便于作为查询参数过滤
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// merchantTransIDPattern 商户交易ID会拼入Evonet接口路径，只允许字母、数字和 _ -
+var merchantTransIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// registerValidators 向gin的validator注册自定义规则
+func registerValidators() {
+	registerValidatorsOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		// 错误中使用JSON字段名
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+
+		v.RegisterValidation("catalog_currency", func(fl validator.FieldLevel) bool {
+			_, ok := catalog.Current().Currency(fl.Field().String())
+			return ok
+		})
+		v.RegisterValidation("merchant_trans_id", func(fl validator.FieldLevel) bool {
+			return merchantTransIDPattern.MatchString(fl.Field().String())
+		})
+		v.RegisterValidation("metadata_key", func(fl validator.FieldLevel) bool {
+			return metadataKeyPattern.MatchString(fl.Field().String())
+		})
+		v.RegisterValidation("allowed_url", func(fl validator.FieldLevel) bool {
+			return isAllowedURL(fl.Field().String(), config.Load().AllowedURLHosts)
+		})
+		v.RegisterStructValidation(validatePaymentRequest, models.PaymentRequest{})
+		v.RegisterStructValidation(validatePaymentLinkRequest, models.PaymentLinkRequest{})
+		v.RegisterStructValidation(validatePayoutRequest, models.PayoutRequest{})
+	})
+}
+
+// validatePaymentRequest 依赖多个字段的规则：币种金额范围、支付方式、Direct API卡信息
+func validatePaymentRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.PaymentRequest)
+	c := catalog.Current()
+
+	validateCatalogAmount(sl, c, req.Currency, req.Amount, "amount", "Amount")
+	if req.PaymentType != "" {
+		validateCatalogMethod(sl, c, req.Currency, req.PaymentType, req.PaymentMethod)
+	}
+	if req.PaymentType == models.PaymentTypeDirectAPI && req.CardInfo == nil {
+		sl.ReportError(req.CardInfo, "cardInfo", "CardInfo", "required_for_directapi", "")
+	}
+	if cur, ok := c.Currency(req.Currency); ok && req.Order != nil {
+		validateOrder(sl, req.Order, req.Amount, cur.Exponent)
+	}
+}
+
+// 目录相关的校验集中在以下两个函数，支付、支付链接和付款请求共用；币种本身由catalog_currency标签校验
+
+// validateCatalogAmount 金额需在目录中该币种的允许范围内，币种不支持或金额为0时不重复报错
+func validateCatalogAmount(sl validator.StructLevel, c *catalog.Catalog, currency string, amount float64, name, field string) {
+	cur, ok := c.Currency(currency)
+	if !ok || amount <= 0 {
+		return
+	}
+	if err := c.ValidateAmount(currency, amount); err != nil {
+		sl.ReportError(amount, name, field, "amount_range", fmt.Sprintf("%g-%g", cur.MinAmount, cur.MaxAmount))
+	}
+}
+
+// validateCatalogMethod 指定了支付方式时，目录中需有使用该币种的国家在该集成方式下支持它
+func validateCatalogMethod(sl validator.StructLevel, c *catalog.Catalog, currency, paymentType, method string) {
+	if method != "" && !c.SupportsPaymentMethod(currency, paymentType, method) {
+		sl.ReportError(method, "paymentMethod", "PaymentMethod", "payment_method", paymentType)
+	}
+}
+
+// validateOrder 按币种小数位比较订单合计与支付金额，行折扣不能超过行金额
+func validateOrder(sl validator.StructLevel, order *models.Order, amount float64, exponent int) {
+	scale := math.Pow10(exponent)
+	minor := func(v float64) int64 { return int64(math.Round(v * scale)) }
+
+	for i, item := range order.Items {
+		if minor(item.Discount) > minor(float64(item.Quantity)*item.UnitPrice) {
+			sl.ReportError(item.Discount, fmt.Sprintf("order.items[%d].discount", i), "Discount", "ltefield", "lineAmount")
+		}
+	}
+	if total := order.Total(); minor(total) != minor(amount) {
+		sl.ReportError(order, "order", "Order", "order_total", strconv.FormatFloat(total, 'f', exponent, 64))
+	}
+}
+
+// validatePaymentLinkRequest 固定金额与金额范围互斥，金额需在币种范围内，过期时间需晚于当前时间
+func validatePaymentLinkRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.PaymentLinkRequest)
+	c := catalog.Current()
+
+	validateCatalogAmount(sl, c, req.Currency, req.Amount, "amount", "Amount")
+	validateCatalogAmount(sl, c, req.Currency, req.MinAmount, "minAmount", "MinAmount")
+	validateCatalogAmount(sl, c, req.Currency, req.MaxAmount, "maxAmount", "MaxAmount")
+	if req.Amount > 0 && (req.MinAmount > 0 || req.MaxAmount > 0) {
+		sl.ReportError(req.MinAmount, "minAmount", "MinAmount", "excluded_with_amount", "")
+	}
+	if req.MinAmount > 0 && req.MaxAmount > 0 && req.MinAmount > req.MaxAmount {
+		sl.ReportError(req.MaxAmount, "maxAmount", "MaxAmount", "gtefield", "minAmount")
+	}
+	validateCatalogMethod(sl, c, req.Currency, models.PaymentTypeLinkPay, req.PaymentMethod)
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		sl.ReportError(req.ExpiresAt, "expiresAt", "ExpiresAt", "future", "")
+	}
+}
+
+// validatePayoutRequest 付款金额需在币种范围内
+func validatePayoutRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.PayoutRequest)
+	validateCatalogAmount(sl, catalog.Current(), req.Currency, req.Amount, "amount", "Amount")
+}
+
+// isAllowedURL URL必须是http(s)绝对地址；配置了白名单时主机名需匹配
+func isAllowedURL(raw string, allowedHosts []string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	if len(allowedHosts) == 0 {
+		return true
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, pattern := range allowedHosts {
+		pattern = strings.ToLower(pattern)
+		if host == pattern {
+			return true
+		}
+		// *.example.com 只匹配子域名，后缀前必须是"."，避免匹配 evilexample.com
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok && strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// requirePaymentType 同一请求结构用于多个接口，各接口只接受自己支持的paymentType，失败时已写入响应
+func requirePaymentType(c *gin.Context, req *models.PaymentRequest, allowed ...string) bool {
+	if slices.Contains(allowed, req.PaymentType) {
+		return true
+	}
+	param := strings.Join(allowed, " ")
+	respondFieldErrors(c, []FieldError{{
+		Field:   "paymentType",
+		Rule:    "oneof",
+		Param:   param,
+		Message: fmt.Sprintf("PaymentType must be one of: %s", param),
+	}})
+	return false
+}
+
+// respondBindError 绑定失败时返回400，校验错误附带字段级明细
+func respondBindError(c *gin.Context, err error) {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		c.JSON(400, gin.H{
+			"success": false,
+			"message": "Invalid request parameters",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	details := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		field := fe.Namespace()
+		if _, rest, ok := strings.Cut(field, "."); ok {
+			field = rest // 去掉结构体名前缀
+		}
+		details = append(details, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	respondFieldErrors(c, details)
+}
+
+// respondFieldErrors 返回400及字段级明细
+func respondFieldErrors(c *gin.Context, details []FieldError) {
+	c.JSON(400, gin.H{
+		"success": false,
+		"message": "Invalid request parameters",
+		"error":   details[0].Message,
+		"errors":  details,
+	})
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", fe.Field(), fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must be %s characters long", fe.Field(), fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", fe.Field(), fe.Param())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fe.Field())
+	case "alpha":
+		return fmt.Sprintf("%s must contain only letters", fe.Field())
+	case "min", "max":
+		return fmt.Sprintf("%s must satisfy %s=%s", fe.Field(), fe.Tag(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fe.Field(), fe.Param())
+	case "alphanum":
+		return fmt.Sprintf("%s must contain only letters and digits", fe.Field())
+	case "numeric":
+		return fmt.Sprintf("%s must contain only digits", fe.Field())
+	case "merchant_trans_id":
+		return fmt.Sprintf("%s must be 1-64 letters, digits, '_' or '-'", fe.Field())
+	case "catalog_currency":
+		return fmt.Sprintf("currency %v is not supported", fe.Value())
+	case "amount_range":
+		return fmt.Sprintf("amount must be between %s for this currency", strings.Replace(fe.Param(), "-", " and ", 1))
+	case "payment_method":
+		return fmt.Sprintf("payment method %v is not available for %s", fe.Value(), fe.Param())
+	case "allowed_url":
+		return fmt.Sprintf("%s must be an absolute http(s) URL on an allowed host", fe.Field())
+	case "required_for_directapi":
+		return "cardInfo is required for directapi payments"
+	case "excluded_with_amount":
+		return "minAmount and maxAmount cannot be used with a fixed amount"
+	case "gtefield":
+		return fmt.Sprintf("%s must be greater than or equal to %s", fe.Field(), fe.Param())
+	case "ltefield":
+		return fmt.Sprintf("%s must be less than or equal to %s", fe.Field(), fe.Param())
+	case "order_total":
+		return fmt.Sprintf("order total %s does not equal amount", fe.Param())
+	case "metadata_key":
+		return fmt.Sprintf("metadata key %q may contain only letters, digits, '_', '-' and '.'", fmt.Sprint(fe.Value()))
+	case "future":
+		return fmt.Sprintf("%s must be in the future", fe.Field())
+	default:
+		return fmt.Sprintf("%s failed %s validation", fe.Field(), fe.Tag())
+	}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"sort"
+
+	"payment-demo/internal/models"
+	"payment-demo/internal/service"
+	"payment-demo/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 获取入站webhook事件列表，支持按status和merchantTransId过滤，按接收时间倒序
+func listWebhookEvents(c *gin.Context) {
+	status := c.Query("status")
+	merchantTransID := c.Query("merchantTransId")
+
+	events := service.WebhookEvents().Filter(func(e models.InboundWebhookEvent) bool {
+		return (status == "" || e.Status == status) && (merchantTransID == "" || e.MerchantTransID == merchantTransID)
+	})
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ReceivedAt.After(events[j].ReceivedAt)
+	})
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    events,
+	})
+}
+
+// 获取单个入站webhook事件
+func getWebhookEvent(c *gin.Context) {
+	event, ok := service.WebhookEvents().Get(c.Param("id"))
+	if !ok {
+		respondStoreError(c, store.ErrNotFound, "Failed to get webhook event")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"data":    event,
+	})
+}
+
+// 重放已保存的webhook事件
+func replayWebhookEvent(c *gin.Context) {
+	event, err := service.ReplayWebhookEvent(c.Param("id"))
+	if err != nil {
+		respondStoreError(c, err, "Failed to replay webhook event")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "Webhook event replayed",
+		"data":    event,
+	})
+}
@@ -0,0 +1,263 @@
+// Package audit 追加写入的审计日志，条目之间以哈希链相连，可检测篡改
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/models"
+)
+
+// 审计动作
+const (
+	ActionPaymentCreate         = "payment.create"
+	ActionPaymentTransition     = "payment.status_change"
+	ActionRefundCreate          = "refund.create"
+	ActionEnvironmentSwitch     = "config.switch_env"
+	ActionCatalogReload         = "config.catalog_reload"
+	ActionWebhookEndpointAdd    = "webhook_endpoint.create"
+	ActionWebhookEndpointDel    = "webhook_endpoint.delete"
+	ActionPaymentLinkCreate     = "payment_link.create"
+	ActionPaymentLinkDeactivate = "payment_link.deactivate"
+	ActionDisputeUpdate         = "dispute.update"
+	ActionDisputeEvidence       = "dispute.evidence_upload"
+	ActionDisputeRespond        = "dispute.respond"
+	ActionBeneficiaryCreate     = "beneficiary.create"
+	ActionBeneficiaryDisable    = "beneficiary.disable"
+	ActionPayoutCreate          = "payout.create"
+	ActionPayoutTransition      = "payout.status_change"
+	ActionStoreLoadErrorAck     = "store.load_error_ack"
+)
+
+// genesisHash 第一条记录的PrevHash
+const genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Event 待记录的审计事件，Before/After会序列化为JSON
+type Event struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	SourceIP   string
+	Before     interface{}
+	After      interface{}
+}
+
+// Log 审计日志，path为空时仅保存在内存中
+type Log struct {
+	mu      sync.RWMutex
+	path    string
+	entries []models.AuditEntry
+}
+
+var (
+	defaultLog  *Log
+	defaultOnce sync.Once
+)
+
+// Default 返回数据目录下的全局审计日志
+func Default() *Log {
+	defaultOnce.Do(func() {
+		path := ""
+		if dir := config.Load().DataDir; dir != "" {
+			path = filepath.Join(dir, "audit.jsonl")
+		}
+		l, err := Open(path)
+		if err != nil {
+			// 无法读取时仍然继续追加，校验接口会报告链断裂
+			fmt.Printf("[Audit] 加载审计日志失败: %v\n", err)
+		}
+		defaultLog = l
+	})
+	return defaultLog
+}
+
+// Record 写入全局审计日志，失败只记录日志不影响业务
+func Record(e Event) {
+	if _, err := Default().Append(e); err != nil {
+		fmt.Printf("[Audit] 写入审计日志失败 - action: %s, target: %s, error: %v\n", e.Action, e.TargetID, err)
+	}
+}
+
+// Open 加载path中已有的审计日志
+func Open(path string) (*Log, error) {
+	l := &Log{path: path}
+	if path == "" {
+		return l, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return l, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return l, fmt.Errorf("%s line %d: %w", path, line, err)
+		}
+		l.entries = append(l.entries, entry)
+	}
+	return l, scanner.Err()
+}
+
+// Append 计算哈希并追加一条记录
+func (l *Log) Append(e Event) (models.AuditEntry, error) {
+	before, err := marshalState(e.Before)
+	if err != nil {
+		return models.AuditEntry{}, err
+	}
+	after, err := marshalState(e.After)
+	if err != nil {
+		return models.AuditEntry{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := models.AuditEntry{
+		Seq:        1,
+		Timestamp:  time.Now().UTC(),
+		Actor:      e.Actor,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		RequestID:  e.RequestID,
+		SourceIP:   e.SourceIP,
+		Before:     before,
+		After:      after,
+		PrevHash:   genesisHash,
+	}
+	if n := len(l.entries); n > 0 {
+		entry.Seq = l.entries[n-1].Seq + 1
+		entry.PrevHash = l.entries[n-1].Hash
+	}
+	if entry.Hash, err = hashEntry(entry); err != nil {
+		return entry, err
+	}
+
+	if l.path != "" {
+		if err := l.write(entry); err != nil {
+			return entry, err
+		}
+	}
+	l.entries = append(l.entries, entry)
+	return entry, nil
+}
+
+// write 以追加方式写入一行JSON
+func (l *Log) write(entry models.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries 返回满足条件的记录（按Seq升序）
+func (l *Log) Entries(match func(models.AuditEntry) bool) []models.AuditEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]models.AuditEntry, 0)
+	for _, entry := range l.entries {
+		if match == nil || match(entry) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// Verification 哈希链校验结果
+type Verification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`
+	BrokenAt int64  `json:"brokenAt,omitempty"` // 第一条校验失败的Seq
+	Error    string `json:"error,omitempty"`
+}
+
+// Verify 从头校验每条记录的哈希及与上一条的链接
+func (l *Log) Verify() Verification {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := Verification{Valid: true, Entries: len(l.entries)}
+	prev := genesisHash
+	for i, entry := range l.entries {
+		fail := func(msg string) Verification {
+			result.Valid = false
+			result.BrokenAt = entry.Seq
+			result.Error = msg
+			return result
+		}
+		if entry.Seq != int64(i+1) {
+			return fail(fmt.Sprintf("expected seq %d, got %d", i+1, entry.Seq))
+		}
+		if entry.PrevHash != prev {
+			return fail("prevHash does not match previous entry")
+		}
+		hash, err := hashEntry(entry)
+		if err != nil {
+			return fail(err.Error())
+		}
+		if hash != entry.Hash {
+			return fail("hash mismatch, entry was modified")
+		}
+		prev = entry.Hash
+	}
+	return result
+}
+
+// hashEntry sha256(PrevHash + 不含Hash字段的条目JSON)
+func hashEntry(entry models.AuditEntry) (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(entry.PrevHash), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// marshalState 序列化变更前后的状态，nil保持为空
+func marshalState(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	return data, nil
+}
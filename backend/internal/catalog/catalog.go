@@ -0,0 +1,182 @@
+package catalog
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"payment-demo/config"
+	"payment-demo/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultCatalog []byte
+
+var (
+	// ErrUnsupportedCurrency 目录中没有该币种
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrAmountOutOfRange 金额超出币种允许的范围
+	ErrAmountOutOfRange = errors.New("amount out of range")
+)
+
+// Currency 币种配置
+type Currency struct {
+	Code      string            `yaml:"code" json:"code"`
+	Exponent  int               `yaml:"exponent" json:"exponent"` // 小数位数
+	MinAmount float64           `yaml:"minAmount" json:"minAmount"`
+	MaxAmount float64           `yaml:"maxAmount" json:"maxAmount"`
+	Rounding  string            `yaml:"rounding" json:"rounding,omitempty"` // 换算舍入方式，为空时使用FX_ROUNDING
+	Names     map[string]string `yaml:"names" json:"names,omitempty"`
+}
+
+// Country 国家/地区配置
+type Country struct {
+	Code           string              `yaml:"code" json:"code"`
+	Name           string              `yaml:"name" json:"name"`
+	Currency       string              `yaml:"currency" json:"currency"`
+	Language       string              `yaml:"language" json:"language"`
+	Names          map[string]string   `yaml:"names" json:"names,omitempty"`
+	PaymentMethods map[string][]string `yaml:"paymentMethods" json:"paymentMethods,omitempty"` // 集成方式 -> 支付方式
+}
+
+// Catalog 国家、币种、支付方式和演示场景目录
+type Catalog struct {
+	Currencies []Currency               `yaml:"currencies" json:"currencies"`
+	Countries  []Country                `yaml:"countries" json:"countries"`
+	Scenarios  []models.PaymentScenario `yaml:"scenarios" json:"scenarios"`
+
+	currencies map[string]Currency
+}
+
+var (
+	current  atomic.Pointer[Catalog]
+	loadOnce sync.Once
+)
+
+// Current 返回当前生效的目录，首次调用时按配置加载
+func Current() *Catalog {
+	if c := current.Load(); c != nil {
+		return c
+	}
+	loadOnce.Do(func() {
+		c, err := Load(config.Load().CatalogPath)
+		if err != nil {
+			// 外部文件有误时退回内置目录，避免服务无法启动
+			fmt.Printf("[Catalog] 加载目录失败，使用内置目录: %v\n", err)
+			c, _ = Parse(defaultCatalog)
+		}
+		current.CompareAndSwap(nil, c)
+	})
+	return current.Load()
+}
+
+// Load 从YAML/JSON文件加载目录，path为空时使用内置目录
+func Load(path string) (*Catalog, error) {
+	if path == "" {
+		return Parse(defaultCatalog)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse 解析并校验目录（JSON是YAML的子集，两种格式都可解析）
+func Parse(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	c.currencies = make(map[string]Currency, len(c.Currencies))
+	for _, cur := range c.Currencies {
+		code := strings.ToUpper(cur.Code)
+		if code == "" {
+			return nil, errors.New("catalog: currency code is required")
+		}
+		if _, dup := c.currencies[code]; dup {
+			return nil, fmt.Errorf("catalog: duplicate currency %s", code)
+		}
+		if cur.Exponent < 0 || cur.Exponent > 4 {
+			return nil, fmt.Errorf("catalog: currency %s has invalid exponent %d", code, cur.Exponent)
+		}
+		if cur.Rounding != "" && !ValidRounding(cur.Rounding) {
+			return nil, fmt.Errorf("catalog: currency %s has unknown rounding %q", code, cur.Rounding)
+		}
+		if cur.MaxAmount > 0 && cur.MinAmount > cur.MaxAmount {
+			return nil, fmt.Errorf("catalog: currency %s has minAmount greater than maxAmount", code)
+		}
+		cur.Code = code
+		c.currencies[code] = cur
+	}
+	for _, country := range c.Countries {
+		if _, ok := c.currencies[strings.ToUpper(country.Currency)]; !ok {
+			return nil, fmt.Errorf("catalog: country %s uses unknown currency %s", country.Code, country.Currency)
+		}
+	}
+	return &c, nil
+}
+
+// Replace 替换当前目录（热加载使用）
+func Replace(c *Catalog) {
+	current.Store(c)
+}
+
+// Currency 按代码查找币种
+func (c *Catalog) Currency(code string) (Currency, bool) {
+	cur, ok := c.currencies[strings.ToUpper(code)]
+	return cur, ok
+}
+
+// CountryList 返回国家列表，lang非空时使用对应语言的名称
+func (c *Catalog) CountryList(lang string) []models.Country {
+	countries := make([]models.Country, 0, len(c.Countries))
+	for _, country := range c.Countries {
+		name := country.Name
+		if localized, ok := country.Names[lang]; ok && localized != "" {
+			name = localized
+		}
+		countries = append(countries, models.Country{
+			Code:           country.Code,
+			Name:           name,
+			Currency:       country.Currency,
+			Language:       country.Language,
+			PaymentMethods: country.PaymentMethods,
+		})
+	}
+	return countries
+}
+
+// ValidateAmount 校验币种是否支持以及金额是否在允许范围内
+func (c *Catalog) ValidateAmount(currency string, amount float64) error {
+	cur, ok := c.Currency(currency)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+	if amount < cur.MinAmount || (cur.MaxAmount > 0 && amount > cur.MaxAmount) {
+		return fmt.Errorf("%w: %s amount must be between %g and %g", ErrAmountOutOfRange, cur.Code, cur.MinAmount, cur.MaxAmount)
+	}
+	return nil
+}
+
+// SupportsPaymentMethod 判断使用该币种的任一国家是否在该集成方式下支持此支付方式
+func (c *Catalog) SupportsPaymentMethod(currency, paymentType, method string) bool {
+	for _, country := range c.Countries {
+		if !strings.EqualFold(country.Currency, currency) {
+			continue
+		}
+		for _, m := range country.PaymentMethods[paymentType] {
+			if strings.EqualFold(m, method) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package catalog
+
+import "math"
+
+// 舍入方式
+const (
+	RoundHalfUp   = "half_up"   // 四舍五入（远离零）
+	RoundHalfEven = "half_even" // 银行家舍入
+	RoundDown     = "down"      // 向零截断
+	RoundUp       = "up"        // 远离零进位
+)
+
+// ValidRounding 是否为支持的舍入方式
+func ValidRounding(mode string) bool {
+	switch mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return true
+	}
+	return false
+}
+
+// Round 按币种小数位舍入，币种未配置rounding时使用defaultMode
+func (cur Currency) Round(amount float64, defaultMode string) float64 {
+	mode := cur.Rounding
+	if mode == "" {
+		mode = defaultMode
+	}
+	scale := math.Pow10(cur.Exponent)
+	// 先消除浮点误差（如 1.005*100 = 100.49999...），再按方式取整
+	scaled := math.Round(amount*scale*1e6) / 1e6
+
+	switch mode {
+	case RoundHalfEven:
+		scaled = math.RoundToEven(scaled)
+	case RoundDown:
+		scaled = math.Trunc(scaled)
+	case RoundUp:
+		if scaled < 0 {
+			scaled = math.Floor(scaled)
+		} else {
+			scaled = math.Ceil(scaled)
+		}
+	default:
+		scaled = math.Round(scaled)
+	}
+	return scaled / scale
+}
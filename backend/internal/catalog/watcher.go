@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"payment-demo/internal/audit"
+	"payment-demo/internal/health"
+)
+
+const watcherWorker = "catalog-watcher"
+
+// Watcher 定期检查目录文件的修改时间，变化时重新加载
+// 新文件校验失败时保留当前目录
+type Watcher struct {
+	path     string
+	interval time.Duration
+	modTime  time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewWatcher 创建目录热加载任务
+func NewWatcher(path string, interval time.Duration) *Watcher {
+	return &Watcher{
+		path:     path,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start 启动热加载，未配置外部目录文件或间隔<=0时不启动
+func (w *Watcher) Start() {
+	if w.path == "" || w.interval <= 0 {
+		close(w.done)
+		return
+	}
+	if info, err := os.Stat(w.path); err == nil {
+		w.modTime = info.ModTime()
+	}
+
+	health.RegisterWorker(watcherWorker, w.interval)
+	go func() {
+		defer close(w.done)
+		defer health.WorkerStopped(watcherWorker)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			health.Beat(watcherWorker)
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+}
+
+// Stop 停止热加载
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func (w *Watcher) check() {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		fmt.Printf("[Catalog] 无法读取目录文件: %v\n", err)
+		return
+	}
+	if info.ModTime().Equal(w.modTime) {
+		return
+	}
+	w.modTime = info.ModTime()
+
+	c, err := Load(w.path)
+	if err != nil {
+		fmt.Printf("[Catalog] 目录文件有误，继续使用当前目录: %v\n", err)
+		return
+	}
+	Replace(c)
+	audit.Record(audit.Event{
+		Actor:      "system:" + watcherWorker,
+		Action:     audit.ActionCatalogReload,
+		TargetType: "config",
+		TargetID:   w.path,
+		After:      map[string]int{"countries": len(c.Countries), "currencies": len(c.Currencies)},
+	})
+	fmt.Printf("[Catalog] 目录已重新加载 - %d 个国家, %d 个币种\n", len(c.Countries), len(c.Currencies))
+}
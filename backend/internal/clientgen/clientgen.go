@@ -0,0 +1,684 @@
+// Package clientgen 根据 internal/api/openapi.yaml 生成 pkg/client 的请求响应类型和接口方法
+//
+// 生成规则：
+//   - components.schemas 中的对象生成同名结构体，字符串枚举生成命名类型和常量
+//   - 每个operation生成一个方法，方法名取operationId；query参数生成 <方法名>Params
+//   - 标记 x-client-omit 的operation（如只供Evonet回调的接口）不生成方法
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 生成的文件名
+const (
+	TypesFile     = "types_gen.go"
+	EndpointsFile = "endpoints_gen.go"
+)
+
+const header = "// Code generated by clientgen from internal/api/openapi.yaml. DO NOT EDIT.\n\n"
+
+// initialisms 字段名中需要全大写的缩写
+var initialisms = map[string]bool{
+	"id": true, "url": true, "api": true, "fx": true, "ip": true,
+	"cvv": true, "sha256": true, "bin": true, "ec": true,
+}
+
+// Generate 解析接口定义，返回文件名到gofmt后源码的映射
+func Generate(spec []byte) (map[string][]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty spec")
+	}
+	g := &generator{
+		root:    doc.Content[0],
+		schemas: get(get(doc.Content[0], "components"), "schemas"),
+		emitted: map[string]bool{},
+	}
+	if g.schemas == nil {
+		return nil, fmt.Errorf("spec has no components.schemas")
+	}
+
+	each(g.schemas, func(name string, s *yaml.Node) {
+		g.schemaType(name, s)
+	})
+	g.flush()
+	g.types.WriteString("\n// 以下类型由operation的参数、请求体和响应生成\n\n")
+	if err := g.operations(); err != nil {
+		return nil, err
+	}
+	g.flush()
+
+	files := map[string][]byte{}
+	var imports []string
+	if g.usesTime {
+		imports = append(imports, "time")
+	}
+	if g.usesIOInTypes {
+		imports = append(imports, "io")
+	}
+	types, err := source(g.types.Bytes(), imports)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", TypesFile, err)
+	}
+	files[TypesFile] = types
+
+	imports = []string{"context"}
+	for _, pkg := range []string{"io", "net/url", "strconv"} {
+		if g.endpointImports[pkg] {
+			imports = append(imports, pkg)
+		}
+	}
+	endpoints, err := source(g.endpoints.Bytes(), imports)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", EndpointsFile, err)
+	}
+	files[EndpointsFile] = endpoints
+	return files, nil
+}
+
+// source 拼上文件头和import后gofmt
+func source(body []byte, imports []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.WriteString("package client\n\n")
+	if len(imports) > 0 {
+		sort.Strings(imports)
+		buf.WriteString("import (\n")
+		for _, pkg := range imports {
+			fmt.Fprintf(&buf, "\t%q\n", pkg)
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body)
+	return format.Source(buf.Bytes())
+}
+
+type generator struct {
+	root    *yaml.Node
+	schemas *yaml.Node
+
+	types     bytes.Buffer
+	endpoints bytes.Buffer
+	pending   []func() // 内联对象的类型定义，在当前类型之后输出
+	emitted   map[string]bool
+
+	usesTime        bool
+	usesIOInTypes   bool
+	endpointImports map[string]bool
+}
+
+func (g *generator) flush() {
+	for len(g.pending) > 0 {
+		next := g.pending[0]
+		g.pending = g.pending[1:]
+		next()
+	}
+}
+
+// schemaType 输出components.schemas中的一个schema
+func (g *generator) schemaType(name string, s *yaml.Node) {
+	if g.emitted[name] {
+		return
+	}
+	g.emitted[name] = true
+	switch {
+	case str(s, "type") == "string" && get(s, "enum") != nil:
+		g.enumType(name, s)
+	case get(s, "properties") != nil:
+		g.structType(name, s)
+	default:
+		g.comment(&g.types, name, str(s, "description"))
+		fmt.Fprintf(&g.types, "type %s %s\n\n", name, g.goType(s, name, true))
+	}
+	g.flush()
+}
+
+func (g *generator) enumType(name string, s *yaml.Node) {
+	g.comment(&g.types, name, str(s, "description"))
+	fmt.Fprintf(&g.types, "type %s string\n\n", name)
+	fmt.Fprintf(&g.types, "// %s的取值\nconst (\n", name)
+	for _, v := range get(s, "enum").Content {
+		fmt.Fprintf(&g.types, "\t%s %s = %q\n", name+exportName(v.Value), name, v.Value)
+	}
+	g.types.WriteString(")\n\n")
+}
+
+func (g *generator) structType(name string, s *yaml.Node) {
+	required := map[string]bool{}
+	if req := get(s, "required"); req != nil {
+		for _, r := range req.Content {
+			required[r.Value] = true
+		}
+	}
+
+	g.comment(&g.types, name, str(s, "description"))
+	fmt.Fprintf(&g.types, "type %s struct {\n", name)
+	each(get(s, "properties"), func(prop string, p *yaml.Node) {
+		field := exportName(prop)
+		typ := g.goType(p, name+field, required[prop])
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.types, "\t%s %s `json:\"%s\"`%s\n", field, typ, tag, trailing(str(p, "description")))
+	})
+	g.types.WriteString("}\n\n")
+}
+
+// goType 返回schema对应的Go类型；owner为内联对象生成类型时使用的名字
+func (g *generator) goType(s *yaml.Node, owner string, required bool) string {
+	if ref := str(s, "$ref"); ref != "" {
+		name := refName(ref)
+		if target := get(g.schemas, name); !required && get(target, "properties") != nil {
+			return "*" + name
+		}
+		return name
+	}
+	switch str(s, "type") {
+	case "string":
+		if str(s, "format") == "date-time" {
+			g.usesTime = true
+			if required {
+				return "time.Time"
+			}
+			return "*time.Time"
+		}
+		return "string"
+	case "integer":
+		if str(s, "format") == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(get(s, "items"), owner+"Item", true)
+	case "object":
+		if get(s, "properties") != nil {
+			g.inline(owner, s)
+			if required {
+				return owner
+			}
+			return "*" + owner
+		}
+		if ap := get(s, "additionalProperties"); ap != nil && ap.Kind == yaml.MappingNode {
+			return "map[string]" + g.goType(ap, owner+"Value", true)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// inline 登记内联对象的结构体定义
+func (g *generator) inline(name string, s *yaml.Node) {
+	if g.emitted[name] {
+		return
+	}
+	g.emitted[name] = true
+	g.pending = append(g.pending, func() { g.structType(name, s) })
+}
+
+// operation 一个接口的生成信息
+type operation struct {
+	name    string
+	method  string
+	path    string
+	summary string
+	admin   bool
+
+	pathParams  []*yaml.Node
+	queryParams []*yaml.Node
+
+	jsonBody    string // 请求体类型
+	rawBody     string // 原样发送的请求体的Content-Type
+	formBody    *yaml.Node
+	streamed    bool   // 非JSON响应，写入io.Writer
+	result      string // 返回值类型，空表示只返回error
+	envelope    bool   // 结果取自envelope的data
+	pointerData bool
+}
+
+func (g *generator) operations() error {
+	g.endpointImports = map[string]bool{}
+	var err error
+	each(get(g.root, "paths"), func(path string, item *yaml.Node) {
+		each(item, func(method string, op *yaml.Node) {
+			if err != nil || op.Kind != yaml.MappingNode || str(op, "operationId") == "" {
+				return
+			}
+			if x := get(op, "x-client-omit"); x != nil && x.Value == "true" {
+				return
+			}
+			var o *operation
+			if o, err = g.operation(strings.ToUpper(method), path, op); err == nil {
+				g.method(o)
+			}
+		})
+	})
+	return err
+}
+
+func (g *generator) operation(method, path string, op *yaml.Node) (*operation, error) {
+	o := &operation{
+		name:    upperFirst(str(op, "operationId")),
+		method:  method,
+		path:    path,
+		summary: str(op, "summary"),
+		admin:   len(list(get(op, "security"))) > 0,
+	}
+
+	for _, p := range list(get(op, "parameters")) {
+		if ref := str(p, "$ref"); ref != "" {
+			p = get(get(get(g.root, "components"), "parameters"), refName(ref))
+		}
+		switch str(p, "in") {
+		case "path":
+			o.pathParams = append(o.pathParams, p)
+		case "query":
+			o.queryParams = append(o.queryParams, p)
+		}
+	}
+	if len(o.queryParams) > 0 {
+		g.paramsType(o)
+	}
+
+	if content := get(get(op, "requestBody"), "content"); content != nil {
+		switch {
+		case get(content, "application/json") != nil:
+			o.jsonBody = g.goType(get(get(content, "application/json"), "schema"), o.name+"Request", false)
+		case get(content, "multipart/form-data") != nil && len(content.Content) == 2:
+			o.formBody = get(get(content, "multipart/form-data"), "schema")
+			g.formType(o)
+		default:
+			o.rawBody = content.Content[0].Value
+			if o.rawBody == "multipart/form-data" {
+				o.rawBody = content.Content[2].Value
+			}
+		}
+	}
+
+	var success *yaml.Node
+	each(get(op, "responses"), func(code string, r *yaml.Node) {
+		if success == nil && strings.HasPrefix(code, "2") {
+			success = r
+		}
+	})
+	if success == nil {
+		return nil, fmt.Errorf("%s %s: no 2xx response", method, path)
+	}
+	content := get(success, "content")
+	if content == nil {
+		return o, nil
+	}
+	jsonContent := get(content, "application/json")
+	if jsonContent == nil {
+		o.streamed = true
+		return o, nil
+	}
+	g.responseType(o, get(jsonContent, "schema"))
+	return o, nil
+}
+
+// responseType 确定返回值：{"success","data"}信封中的data、带分页等字段的整个信封，或整个响应体
+func (g *generator) responseType(o *operation, schema *yaml.Node) {
+	if ref := str(schema, "$ref"); ref != "" {
+		if refName(ref) != "Envelope" {
+			o.result = refName(ref)
+			o.pointerData = true
+		}
+		return
+	}
+	var extension *yaml.Node
+	for _, part := range list(get(schema, "allOf")) {
+		if str(part, "$ref") == "" {
+			extension = part
+		}
+	}
+	props := get(extension, "properties")
+	if props == nil {
+		return
+	}
+	if len(props.Content) > 2 {
+		name := o.name + "Response"
+		o.result = name
+		o.pointerData = true
+		g.emitted[name] = true
+		g.structType(name, extension)
+		return
+	}
+	data := get(props, "data")
+	o.envelope = true
+	o.result = g.goType(data, o.name+"Data", true)
+	o.pointerData = !strings.HasPrefix(o.result, "[]") && !strings.HasPrefix(o.result, "map[")
+}
+
+func (g *generator) paramsType(o *operation) {
+	name := o.name + "Params"
+	fmt.Fprintf(&g.types, "// %s %s的查询参数，零值字段不发送\ntype %s struct {\n", name, o.name, name)
+	for _, p := range o.queryParams {
+		fmt.Fprintf(&g.types, "\t%s %s%s\n", exportName(str(p, "name")), g.paramType(p), trailing(str(p, "description")))
+	}
+	g.types.WriteString("}\n\n")
+}
+
+// paramType query参数的Go类型；number用指针以区分0和未设置
+func (g *generator) paramType(p *yaml.Node) string {
+	s := get(p, "schema")
+	if str(p, "style") == "deepObject" {
+		return "map[string]string"
+	}
+	if str(s, "type") == "number" {
+		return "*float64"
+	}
+	return g.goType(s, "", true)
+}
+
+func (g *generator) formType(o *operation) {
+	name := o.name + "Form"
+	g.usesIOInTypes = true
+	fmt.Fprintf(&g.types, "// %s %s的multipart表单\ntype %s struct {\n", name, o.name, name)
+	each(get(o.formBody, "properties"), func(prop string, p *yaml.Node) {
+		field := exportName(prop)
+		if str(p, "format") == "binary" {
+			fmt.Fprintf(&g.types, "\t%s io.Reader%s\n", field, trailing(str(p, "description")))
+			fmt.Fprintf(&g.types, "\t%sName string // 上传的文件名\n", field)
+			return
+		}
+		fmt.Fprintf(&g.types, "\t%s string%s\n", field, trailing(str(p, "description")))
+	})
+	g.types.WriteString("}\n\n")
+}
+
+// method 输出接口方法，以及参数编码所需的辅助方法
+func (g *generator) method(o *operation) {
+	w := &g.endpoints
+	args := []string{"ctx context.Context"}
+	if o.streamed {
+		args = append(args, "w io.Writer")
+		g.endpointImports["io"] = true
+	}
+	pathExpr := g.pathExpr(o)
+	for _, p := range o.pathParams {
+		args = append(args, lowerName(str(p, "name"))+" string")
+	}
+	if len(o.queryParams) > 0 {
+		args = append(args, "params "+o.name+"Params")
+	}
+	switch {
+	case o.jsonBody != "":
+		args = append(args, "req "+pointer(o.jsonBody))
+	case o.rawBody != "":
+		args = append(args, "body io.Reader")
+		g.endpointImports["io"] = true
+	case o.formBody != nil:
+		args = append(args, "form *"+o.name+"Form")
+	}
+
+	ret, zero := "error", ""
+	if o.result != "" {
+		ret = "(" + o.result + ", error)"
+		zero = "nil, "
+		if o.pointerData {
+			ret = "(*" + o.result + ", error)"
+		}
+	}
+
+	summary := o.summary
+	if o.admin {
+		summary += "（需要管理令牌）"
+	}
+	g.comment(w, o.name, summary)
+	fmt.Fprintf(w, "func (c *Client) %s(%s) %s {\n", o.name, strings.Join(args, ", "), ret)
+	fmt.Fprintf(w, "\tr := request{method: %q, path: %s", o.method, pathExpr)
+	if o.admin {
+		w.WriteString(", admin: true")
+	}
+	w.WriteString("}\n")
+	if len(o.queryParams) > 0 {
+		w.WriteString("\tr.query = params.values()\n")
+	}
+	switch {
+	case o.jsonBody != "":
+		w.WriteString("\tr.json = req\n")
+	case o.rawBody != "":
+		fmt.Fprintf(w, "\tr.body, r.contentType = body, %q\n", o.rawBody)
+	case o.formBody != nil:
+		w.WriteString("\tvar err error\n")
+		w.WriteString("\tif r.body, r.contentType, err = form.encode(); err != nil {\n")
+		fmt.Fprintf(w, "\t\treturn %serr\n\t}\n", zero)
+	}
+
+	call := "c.call"
+	if o.envelope {
+		call = "c.callData"
+	}
+	switch {
+	case o.streamed:
+		w.WriteString("\treturn c.stream(ctx, r, w)\n")
+	case o.result == "":
+		w.WriteString("\treturn c.callData(ctx, r, nil)\n")
+	case o.pointerData:
+		fmt.Fprintf(w, "\tvar out %s\n", o.result)
+		fmt.Fprintf(w, "\tif err := %s(ctx, r, &out); err != nil {\n\t\treturn nil, err\n\t}\n", call)
+		w.WriteString("\treturn &out, nil\n")
+	default:
+		fmt.Fprintf(w, "\tvar out %s\n", o.result)
+		fmt.Fprintf(w, "\terr := %s(ctx, r, &out)\n", call)
+		w.WriteString("\treturn out, err\n")
+	}
+	w.WriteString("}\n\n")
+
+	if len(o.queryParams) > 0 {
+		g.valuesMethod(o)
+	}
+	if o.formBody != nil {
+		g.encodeMethod(o)
+	}
+}
+
+// pathExpr 将 /payment/{merchantTransId}/refund 转换为字符串拼接表达式
+func (g *generator) pathExpr(o *operation) string {
+	var parts []string
+	rest := o.path
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest, "}")
+		parts = append(parts, fmt.Sprintf("%q", rest[:start]), "url.PathEscape("+lowerName(rest[start+1:end])+")")
+		g.endpointImports["net/url"] = true
+		rest = rest[end+1:]
+	}
+	if rest != "" {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func (g *generator) valuesMethod(o *operation) {
+	w := &g.endpoints
+	g.endpointImports["net/url"] = true
+	fmt.Fprintf(w, "func (p %sParams) values() url.Values {\n\tquery := url.Values{}\n", o.name)
+	for _, param := range o.queryParams {
+		name := str(param, "name")
+		field := "p." + exportName(name)
+		switch typ := g.paramType(param); typ {
+		case "map[string]string":
+			fmt.Fprintf(w, "\tfor key, value := range %s {\n\t\tquery.Set(%q+key+\"]\", value)\n\t}\n", field, name+"[")
+		case "*float64":
+			g.endpointImports["strconv"] = true
+			fmt.Fprintf(w, "\tif %s != nil {\n\t\tquery.Set(%q, strconv.FormatFloat(*%s, 'f', -1, 64))\n\t}\n", field, name, field)
+		case "int", "int64":
+			g.endpointImports["strconv"] = true
+			fmt.Fprintf(w, "\tif %s != 0 {\n\t\tquery.Set(%q, strconv.FormatInt(int64(%s), 10))\n\t}\n", field, name, field)
+		case "bool":
+			fmt.Fprintf(w, "\tif %s {\n\t\tquery.Set(%q, \"true\")\n\t}\n", field, name)
+		case "string":
+			fmt.Fprintf(w, "\tif %s != \"\" {\n\t\tquery.Set(%q, %s)\n\t}\n", field, name, field)
+		default:
+			fmt.Fprintf(w, "\tif %s != \"\" {\n\t\tquery.Set(%q, string(%s))\n\t}\n", field, name, field)
+		}
+	}
+	w.WriteString("\treturn query\n}\n\n")
+}
+
+func (g *generator) encodeMethod(o *operation) {
+	w := &g.endpoints
+	g.endpointImports["io"] = true
+	fmt.Fprintf(w, "func (f *%sForm) encode() (io.Reader, string, error) {\n\treturn encodeForm([]formField{\n", o.name)
+	each(get(o.formBody, "properties"), func(prop string, p *yaml.Node) {
+		field := exportName(prop)
+		if str(p, "format") == "binary" {
+			fmt.Fprintf(w, "\t\t{name: %q, file: f.%s, fileName: f.%sName},\n", prop, field, field)
+			return
+		}
+		fmt.Fprintf(w, "\t\t{name: %q, value: f.%s},\n", prop, field)
+	})
+	w.WriteString("\t})\n}\n\n")
+}
+
+// comment 输出doc注释，多行描述逐行加 //
+func (g *generator) comment(w *bytes.Buffer, name, text string) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if lines[0] == "" {
+		lines[0] = "对应接口定义中的" + name
+	}
+	fmt.Fprintf(w, "// %s %s\n", name, strings.TrimSpace(lines[0]))
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" {
+			fmt.Fprintf(w, "// %s\n", line)
+		}
+	}
+}
+
+// trailing 字段行尾注释，只取描述的第一行
+func trailing(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if line == "" {
+		return ""
+	}
+	return " // " + line
+}
+
+func pointer(typ string) string {
+	if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") {
+		return typ
+	}
+	return "*" + typ
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// words 按驼峰和非字母数字字符拆分名称
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(cur) > 0 {
+				out = append(out, string(cur))
+			}
+			cur = nil
+			continue
+		case unicode.IsUpper(r) && len(cur) > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			out = append(out, string(cur))
+			cur = nil
+		}
+		cur = append(cur, r)
+	}
+	if len(cur) > 0 {
+		out = append(out, string(cur))
+	}
+	return out
+}
+
+// exportName merchantTransId -> MerchantTransID，partially_refunded -> PartiallyRefunded
+func exportName(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		if initialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		b.WriteString(upperFirst(w))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "V" + name
+	}
+	return name
+}
+
+// lowerName merchantTransId -> merchantTransID，id -> id
+func lowerName(s string) string {
+	ws := words(s)
+	if len(ws) == 0 {
+		return s
+	}
+	first := strings.ToLower(ws[0])
+	return first + strings.TrimPrefix(exportName(s), exportName(ws[0]))
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// get 返回映射节点中key对应的值
+func get(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// each 按定义顺序遍历映射节点
+func each(n *yaml.Node, fn func(key string, v *yaml.Node)) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		fn(n.Content[i].Value, n.Content[i+1])
+	}
+}
+
+func list(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+func str(n *yaml.Node, key string) string {
+	if v := get(n, key); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
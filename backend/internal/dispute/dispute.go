@@ -0,0 +1,235 @@
+// Package dispute 跟踪持卡人发起的拒付（chargeback）：接收Evonet拒付通知、保存证据、记录商户应诉
+package dispute
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"payment-demo/internal/audit"
+	"payment-demo/internal/ledger"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/webhook"
+)
+
+var (
+	// ErrInvalidNotification 拒付通知缺少disputeId或disputeId不合法
+	ErrInvalidNotification = errors.New("invalid dispute notification")
+	// ErrStaleNotification 乱序到达的旧通知，不能让拒付状态回退
+	ErrStaleNotification = errors.New("stale dispute notification")
+	// ErrNotRespondable 拒付当前状态或截止时间不允许应诉/上传证据
+	ErrNotRespondable = errors.New("dispute cannot be responded to")
+	// ErrEvidenceRequired 应诉前至少需要上传一份证据
+	ErrEvidenceRequired = errors.New("at least one evidence file is required to challenge")
+)
+
+// disputeIDPattern disputeId同时用作证据目录名，只允许安全字符
+var disputeIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,127}$`)
+
+// statusRank 状态只能前进；won/lost为终态
+var statusRank = map[string]int{
+	models.DisputeNeedsResponse: 0,
+	models.DisputeUnderReview:   1,
+	models.DisputeAccepted:      2,
+	models.DisputeWon:           3,
+	models.DisputeLost:          3,
+}
+
+// Disputes 拒付记录集合，以Evonet的disputeId为键
+func Disputes() *store.Collection[models.Dispute] {
+	return store.Open[models.Dispute]("disputes")
+}
+
+// IsDisputeEvent 判断eventCode是否为拒付通知
+func IsDisputeEvent(eventCode string) bool {
+	code := strings.ToUpper(eventCode)
+	return strings.HasPrefix(code, "DISPUTE") || strings.HasPrefix(code, "CHARGEBACK")
+}
+
+// Apply 根据Evonet通知创建或更新拒付记录，返回更新后的记录和处理说明
+func Apply(eventCode string, n *models.DisputeNotification) (models.Dispute, string, error) {
+	if n == nil || !disputeIDPattern.MatchString(n.DisputeID) {
+		return models.Dispute{}, "", ErrInvalidNotification
+	}
+
+	now := time.Now()
+	created := models.Dispute{
+		ID:              n.DisputeID,
+		MerchantTransID: n.MerchantTransID,
+		Status:          statusFor(eventCode, n.Status, models.DisputeNeedsResponse),
+		Evidence:        []models.DisputeEvidence{},
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	mergeNotification(&created, n)
+	if payment, ok := store.Payments().Get(n.MerchantTransID); ok {
+		created.Environment = payment.Environment
+		if created.Currency == "" {
+			created.Currency = payment.Currency
+		}
+		if created.Amount == 0 {
+			created.Amount = payment.Amount
+		}
+	} else {
+		fmt.Printf("[Dispute] 拒付关联的支付记录不存在 - disputeId: %s, merchantTransId: %s\n", n.DisputeID, n.MerchantTransID)
+	}
+	if isClosed(created.Status) {
+		created.ClosedAt = &now
+	}
+
+	err := Disputes().Insert(created.ID, created)
+	if err == nil {
+		publish(created)
+		audit.Record(audit.Event{
+			Actor:      "system:webhook",
+			Action:     audit.ActionDisputeUpdate,
+			TargetType: "dispute",
+			TargetID:   created.ID,
+			After:      created,
+		})
+		return created, "opened " + created.Status, nil
+	}
+	if !errors.Is(err, store.ErrDuplicate) {
+		return models.Dispute{}, "", err
+	}
+
+	var before models.Dispute
+	updated, err := Disputes().Update(n.DisputeID, func(d *models.Dispute) error {
+		before = *d
+		status := statusFor(eventCode, n.Status, d.Status)
+		if statusRank[status] < statusRank[d.Status] || (isClosed(d.Status) && status != d.Status && d.Status != models.DisputeAccepted) {
+			return fmt.Errorf("%w: %s -> %s", ErrStaleNotification, d.Status, status)
+		}
+		mergeNotification(d, n)
+		d.Status = status
+		d.UpdatedAt = now
+		if isClosed(status) && d.ClosedAt == nil {
+			d.ClosedAt = &now
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Dispute{}, "", err
+	}
+
+	if updated.Status != before.Status {
+		publish(updated)
+	}
+	audit.Record(audit.Event{
+		Actor:      "system:webhook",
+		Action:     audit.ActionDisputeUpdate,
+		TargetType: "dispute",
+		TargetID:   updated.ID,
+		Before:     before,
+		After:      updated,
+	})
+	return updated, fmt.Sprintf("%s -> %s", before.Status, updated.Status), nil
+}
+
+// Respond 商户接受拒付或提交证据应诉，返回应诉前后的记录
+func Respond(id string, req *models.DisputeResponseRequest) (before, after models.Dispute, err error) {
+	after, err = Disputes().Update(id, func(d *models.Dispute) error {
+		before = *d
+		if err := checkRespondable(d, time.Now()); err != nil {
+			return err
+		}
+		if req.Action == models.DisputeResponseChallenge && len(d.Evidence) == 0 {
+			return ErrEvidenceRequired
+		}
+
+		now := time.Now()
+		d.Response = req.Action
+		d.ResponseNote = req.Note
+		d.RespondedAt = &now
+		d.UpdatedAt = now
+		if req.Action == models.DisputeResponseAccept {
+			d.Status = models.DisputeAccepted
+			d.ClosedAt = &now
+		} else {
+			d.Status = models.DisputeUnderReview
+		}
+		return nil
+	})
+	if err != nil {
+		return before, after, err
+	}
+
+	publish(after)
+	fmt.Printf("[Dispute] 商户已处理拒付 - disputeId: %s, action: %s\n", id, req.Action)
+	return before, after, nil
+}
+
+// checkRespondable 只有等待应诉且未过证据截止时间的拒付可以应诉或补充证据
+func checkRespondable(d *models.Dispute, now time.Time) error {
+	if d.Status != models.DisputeNeedsResponse {
+		return fmt.Errorf("%w: status is %s", ErrNotRespondable, d.Status)
+	}
+	if d.EvidenceDueBy != nil && now.After(*d.EvidenceDueBy) {
+		return fmt.Errorf("%w: evidence was due by %s", ErrNotRespondable, d.EvidenceDueBy.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// mergeNotification 用通知中非空的字段覆盖记录
+func mergeNotification(d *models.Dispute, n *models.DisputeNotification) {
+	if n.MerchantTransID != "" {
+		d.MerchantTransID = n.MerchantTransID
+	}
+	if n.Reason != "" {
+		d.Reason = n.Reason
+	}
+	if n.ReasonCode != "" {
+		d.ReasonCode = n.ReasonCode
+	}
+	if n.Amount > 0 {
+		d.Amount = n.Amount
+	}
+	if n.Currency != "" {
+		d.Currency = strings.ToUpper(n.Currency)
+	}
+	if n.EvidenceDueBy != nil {
+		d.EvidenceDueBy = n.EvidenceDueBy
+	}
+}
+
+// statusFor 由eventCode和通知中的状态得出本地状态，无法识别时保持current
+func statusFor(eventCode, status, current string) string {
+	switch strings.ToUpper(eventCode) {
+	case models.EventDisputeOpened:
+		return models.DisputeNeedsResponse
+	case models.EventDisputeWon:
+		return models.DisputeWon
+	case models.EventDisputeLost:
+		return models.DisputeLost
+	}
+
+	switch strings.ToLower(status) {
+	case "needs_response", "open", "opened", "pending", "action_required":
+		return models.DisputeNeedsResponse
+	case "under_review", "in_review", "submitted", "evidence_submitted":
+		return models.DisputeUnderReview
+	case "accepted":
+		return models.DisputeAccepted
+	case "won", "merchant_won", "reversed":
+		return models.DisputeWon
+	case "lost", "merchant_lost", "chargeback":
+		return models.DisputeLost
+	default:
+		return current
+	}
+}
+
+func isClosed(status string) bool {
+	return status == models.DisputeAccepted || status == models.DisputeWon || status == models.DisputeLost
+}
+
+// publish 记账并通知商户拒付状态变化，事件类型为 dispute.<status>
+func publish(d models.Dispute) {
+	ledger.RecordDispute(d)
+	if err := webhook.Publish("dispute."+d.Status, d); err != nil {
+		fmt.Printf("[Dispute] 生成商户webhook失败 - disputeId: %s, error: %v\n", d.ID, err)
+	}
+}
@@ -0,0 +1,158 @@
+package dispute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+// ErrInvalidEvidence 证据文件为空、过大或类型不支持
+var ErrInvalidEvidence = errors.New("invalid evidence file")
+
+// allowedEvidenceTypes 按文件内容识别的类型，不信任客户端声明的Content-Type
+var allowedEvidenceTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"text/plain":      true,
+}
+
+// EvidenceDir 证据文件根目录
+func EvidenceDir() string {
+	cfg := config.Load()
+	if cfg.DisputeEvidenceDir != "" {
+		return cfg.DisputeEvidenceDir
+	}
+	return filepath.Join(cfg.DataDir, "disputes")
+}
+
+// EvidencePath 证据文件在本地的路径：<EvidenceDir>/<disputeId>/<evidenceId>
+func EvidencePath(disputeID, evidenceID string) string {
+	return filepath.Join(EvidenceDir(), disputeID, evidenceID)
+}
+
+// AddEvidence 保存证据文件并追加到拒付记录
+func AddEvidence(disputeID, fileName, description string, r io.Reader) (models.DisputeEvidence, error) {
+	d, ok := Disputes().Get(disputeID)
+	if !ok {
+		return models.DisputeEvidence{}, store.ErrNotFound
+	}
+	if err := checkRespondable(&d, time.Now()); err != nil {
+		return models.DisputeEvidence{}, err
+	}
+
+	evidence := models.DisputeEvidence{
+		ID:          utils.GenerateID("dev"),
+		FileName:    filepath.Base(fileName),
+		Description: description,
+		UploadedAt:  time.Now(),
+	}
+	path := EvidencePath(disputeID, evidence.ID)
+	if err := writeEvidence(path, r, &evidence); err != nil {
+		return models.DisputeEvidence{}, err
+	}
+
+	_, err := Disputes().Update(disputeID, func(d *models.Dispute) error {
+		if err := checkRespondable(d, time.Now()); err != nil {
+			return err
+		}
+		d.Evidence = append(d.Evidence, evidence)
+		d.UpdatedAt = evidence.UploadedAt
+		return nil
+	})
+	if err != nil {
+		os.Remove(path)
+		return models.DisputeEvidence{}, err
+	}
+	return evidence, nil
+}
+
+// writeEvidence 写入临时文件并计算大小、摘要和类型，校验通过后再改名为最终路径
+func writeEvidence(path string, r io.Reader, evidence *models.DisputeEvidence) error {
+	maxBytes := config.Load().DisputeEvidenceMaxBytes
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create evidence directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create evidence file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	head := &headWriter{limit: 512}
+	n, err := io.Copy(io.MultiWriter(tmp, hash, head), io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return fmt.Errorf("failed to write evidence file: %w", err)
+	}
+	switch {
+	case n == 0:
+		return fmt.Errorf("%w: file is empty", ErrInvalidEvidence)
+	case n > maxBytes:
+		return fmt.Errorf("%w: file exceeds %d bytes", ErrInvalidEvidence, maxBytes)
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head.buf), ";")
+	if !allowedEvidenceTypes[contentType] {
+		return fmt.Errorf("%w: unsupported file type %s", ErrInvalidEvidence, contentType)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to write evidence file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write evidence file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save evidence file: %w", err)
+	}
+
+	evidence.Size = n
+	evidence.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	evidence.ContentType = contentType
+	return nil
+}
+
+// FindEvidence 查找拒付记录中的证据
+func FindEvidence(disputeID, evidenceID string) (models.DisputeEvidence, error) {
+	d, ok := Disputes().Get(disputeID)
+	if !ok {
+		return models.DisputeEvidence{}, store.ErrNotFound
+	}
+	for _, e := range d.Evidence {
+		if e.ID == evidenceID {
+			return e, nil
+		}
+	}
+	return models.DisputeEvidence{}, store.ErrNotFound
+}
+
+// headWriter 保留写入内容的前limit字节，用于识别文件类型
+type headWriter struct {
+	buf   []byte
+	limit int
+}
+
+func (w *headWriter) Write(p []byte) (int, error) {
+	if room := w.limit - len(w.buf); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		w.buf = append(w.buf, p[:room]...)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,223 @@
+// Package fees 按费率表计算支付和退款的手续费及净额
+package fees
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"payment-demo/config"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/fx"
+	"payment-demo/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultSchedule []byte
+
+// Rule 一条费率规则，匹配字段为空或"*"表示任意
+type Rule struct {
+	Name           string  `yaml:"name" json:"name"`
+	PaymentMethod  string  `yaml:"paymentMethod" json:"paymentMethod,omitempty"`
+	Currency       string  `yaml:"currency" json:"currency,omitempty"`
+	Country        string  `yaml:"country" json:"country,omitempty"`
+	Percentage     float64 `yaml:"percentage" json:"percentage"`
+	Fixed          float64 `yaml:"fixed" json:"fixed"`
+	FixedCurrency  string  `yaml:"fixedCurrency" json:"fixedCurrency,omitempty"`
+	FXMarkup       float64 `yaml:"fxMarkup" json:"fxMarkup"`
+	RefundFixed    float64 `yaml:"refundFixed" json:"refundFixed"`
+	ReturnOnRefund bool    `yaml:"returnOnRefund" json:"returnOnRefund"`
+}
+
+// Schedule 费率表
+type Schedule struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+var (
+	defaultFees *Schedule
+	feesOnce    sync.Once
+)
+
+// Default 返回按配置加载的费率表，文件加载失败时退回内置费率表
+func Default() *Schedule {
+	feesOnce.Do(func() {
+		s, err := Load(config.Load().FeeSchedulePath)
+		if err != nil {
+			fmt.Printf("[Fees] 加载费率表失败，使用内置费率表: %v\n", err)
+			s, _ = Parse(defaultSchedule)
+		}
+		defaultFees = s
+	})
+	return defaultFees
+}
+
+// Load 从YAML/JSON文件加载费率表，path为空时使用内置费率表
+func Load(path string) (*Schedule, error) {
+	if path == "" {
+		return Parse(defaultSchedule)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fee schedule: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse 解析并校验费率表
+func Parse(data []byte) (*Schedule, error) {
+	var s Schedule
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse fee schedule: %w", err)
+	}
+	if len(s.Rules) == 0 {
+		return nil, errors.New("fee schedule: at least one rule is required")
+	}
+	for i := range s.Rules {
+		r := &s.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if r.Percentage < 0 || r.Percentage >= 100 || r.FXMarkup < 0 || r.FXMarkup >= 100 {
+			return nil, fmt.Errorf("fee schedule: rule %s percentages must be between 0 and 100", r.Name)
+		}
+		if r.Fixed < 0 || r.RefundFixed < 0 {
+			return nil, fmt.Errorf("fee schedule: rule %s fixed fees must not be negative", r.Name)
+		}
+		r.Currency = strings.ToUpper(r.Currency)
+		r.Country = strings.ToUpper(r.Country)
+		r.FixedCurrency = strings.ToUpper(r.FixedCurrency)
+	}
+	return &s, nil
+}
+
+// Match 返回最具体的匹配规则
+func (s *Schedule) Match(paymentMethod, currency, country string) (Rule, bool) {
+	best, bestScore := -1, -1
+	for i, r := range s.Rules {
+		score, ok := 0, true
+		for _, f := range [][2]string{{r.PaymentMethod, paymentMethod}, {r.Currency, currency}, {r.Country, country}} {
+			switch {
+			case f[0] == "" || f[0] == "*":
+			case strings.EqualFold(f[0], f[1]):
+				score++
+			default:
+				ok = false
+			}
+		}
+		if ok && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return Rule{}, false
+	}
+	return s.Rules[best], true
+}
+
+// ForPayment 计算扣款时的手续费，没有匹配规则时返回nil
+func (s *Schedule) ForPayment(p models.PaymentRecord) (*models.Fees, error) {
+	rule, ok := s.Match(paymentMethodOf(p), p.Currency, p.BillingCountry)
+	if !ok {
+		return nil, nil
+	}
+	fixed, err := convertFixed(rule.Fixed, rule.FixedCurrency, p.Currency)
+	if err != nil {
+		return nil, err
+	}
+	fees := &models.Fees{
+		Rule:       rule.Name,
+		Currency:   p.Currency,
+		Percentage: p.Amount * rule.Percentage / 100,
+		Fixed:      fixed,
+	}
+	if isConverted(p) {
+		fees.FXMarkup = p.Amount * rule.FXMarkup / 100
+	}
+	return round(fees)
+}
+
+// ForRefund 计算一笔退款的手续费：收取refundFixed，returnOnRefund时按比例退还百分比和换汇费用（为负数）
+func (s *Schedule) ForRefund(p models.PaymentRecord, amount float64) (*models.Fees, error) {
+	rule, ok := s.Match(paymentMethodOf(p), p.Currency, p.BillingCountry)
+	if !ok {
+		return nil, nil
+	}
+	fixed, err := convertFixed(rule.RefundFixed, rule.FixedCurrency, p.Currency)
+	if err != nil {
+		return nil, err
+	}
+	fees := &models.Fees{Rule: rule.Name, Currency: p.Currency, Fixed: fixed}
+	if rule.ReturnOnRefund && p.Fees != nil && p.Amount > 0 {
+		ratio := amount / p.Amount
+		fees.Percentage = -p.Fees.Percentage * ratio
+		fees.FXMarkup = -p.Fees.FXMarkup * ratio
+	}
+	return round(fees)
+}
+
+// NetAmount 扣款金额减去已退款金额和全部手续费
+func NetAmount(p models.PaymentRecord) float64 {
+	net := p.Amount - p.RefundedAmount - p.RefundFees
+	if p.Fees != nil {
+		net -= p.Fees.Total
+	}
+	if cur, ok := catalog.Current().Currency(p.Currency); ok {
+		net = cur.Round(net, catalog.RoundHalfUp)
+	}
+	return net
+}
+
+// isConverted 支付币种与基础币种不同（经过换汇）
+func isConverted(p models.PaymentRecord) bool {
+	base := p.BaseCurrency
+	if base == "" {
+		base = config.Load().FXBaseCurrency
+	}
+	return !strings.EqualFold(base, p.Currency)
+}
+
+// convertFixed 将固定费用换算为支付币种
+func convertFixed(amount float64, from, to string) (float64, error) {
+	if amount == 0 {
+		return 0, nil
+	}
+	if from == "" {
+		from = config.Load().FXBaseCurrency
+	}
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+	conv, err := fx.Convert(amount, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert fixed fee: %w", err)
+	}
+	return conv.Converted, nil
+}
+
+// round 各项按币种小数位舍入后求和
+func round(f *models.Fees) (*models.Fees, error) {
+	cur, ok := catalog.Current().Currency(f.Currency)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrUnsupportedCurrency, f.Currency)
+	}
+	mode := config.Load().FXRounding
+	f.Percentage = cur.Round(f.Percentage, mode)
+	f.Fixed = cur.Round(f.Fixed, mode)
+	f.FXMarkup = cur.Round(f.FXMarkup, mode)
+	f.Total = cur.Round(f.Percentage+f.Fixed+f.FXMarkup, mode)
+	return f, nil
+}
+
+// paymentMethodOf Direct API未指定支付方式时按卡支付计费
+func paymentMethodOf(p models.PaymentRecord) string {
+	if p.PaymentMethod == "" && p.PaymentType == models.PaymentTypeDirectAPI {
+		return "card"
+	}
+	return p.PaymentMethod
+}
@@ -0,0 +1,210 @@
+// Package fraud 在Direct API支付发送到Evonet之前执行风控规则
+package fraud
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+// ErrBlocked 支付被风控规则拒绝
+var ErrBlocked = errors.New("payment blocked by risk rules")
+
+// Input 一次支付尝试的风控输入
+type Input struct {
+	MerchantTransID string
+	Amount          float64
+	Currency        string
+	CardFingerprint string
+	CardBIN         string
+	CardLast4       string
+	BillingCountry  string
+	IP              string
+	Email           string
+	Now             time.Time
+}
+
+// Rule 风控规则，未命中时返回nil
+type Rule interface {
+	Name() string
+	Evaluate(in *Input) *models.RiskRuleResult
+}
+
+// Engine 按顺序执行规则并合并决策
+type Engine struct {
+	rules    *Rules
+	extra    []Rule
+	mu       sync.Mutex // 串行化评估，保证频率统计包含并发中的尝试
+	fpSecret []byte
+}
+
+var (
+	defaultEngine *Engine
+	engineOnce    sync.Once
+)
+
+// Default 返回按配置创建的全局引擎，规则文件加载失败时退回内置规则
+func Default() *Engine {
+	engineOnce.Do(func() {
+		cfg := config.Load()
+		rules, err := LoadRules(cfg.FraudRulesPath)
+		if err != nil {
+			fmt.Printf("[Fraud] 加载风控规则失败，使用内置规则: %v\n", err)
+			rules, _ = ParseRules(defaultRules)
+		}
+		defaultEngine = NewEngine(rules, fingerprintSecret(cfg))
+	})
+	return defaultEngine
+}
+
+// fingerprintSecretFile 未配置FRAUD_FINGERPRINT_SECRET时保存随机密钥的文件（位于DATA_DIR）
+const fingerprintSecretFile = "fraud_fingerprint.key"
+
+// fingerprintSecret 返回计算卡指纹的HMAC密钥
+// 未配置时首次启动生成随机密钥并保存，之后复用，保证重启后指纹仍可匹配；
+// 密钥泄露时可由BIN和Luhn校验穷举出卡号，因此不使用公开的默认值
+func fingerprintSecret(cfg *config.Config) string {
+	if cfg.FraudFingerprintSecret != "" {
+		return cfg.FraudFingerprintSecret
+	}
+	if cfg.DataDir == "" {
+		fmt.Println("[Fraud] 未配置FRAUD_FINGERPRINT_SECRET且没有DATA_DIR，使用仅本次运行有效的随机密钥")
+		return utils.RandomHex(32)
+	}
+
+	path := filepath.Join(cfg.DataDir, fingerprintSecretFile)
+	if data, err := os.ReadFile(path); err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		return strings.TrimSpace(string(data))
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("[Fraud] 读取卡指纹密钥失败，使用仅本次运行有效的随机密钥: %v\n", err)
+		return utils.RandomHex(32)
+	}
+
+	secret := utils.RandomHex(32)
+	if err := os.MkdirAll(cfg.DataDir, 0o755); err != nil {
+		fmt.Printf("[Fraud] 保存卡指纹密钥失败，使用仅本次运行有效的随机密钥: %v\n", err)
+		return secret
+	}
+	if err := os.WriteFile(path, []byte(secret+"\n"), 0o600); err != nil {
+		fmt.Printf("[Fraud] 保存卡指纹密钥失败，使用仅本次运行有效的随机密钥: %v\n", err)
+		return secret
+	}
+	fmt.Printf("[Fraud] 已生成卡指纹密钥: %s\n", path)
+	return secret
+}
+
+// NewEngine 创建引擎，secret用于计算卡指纹
+func NewEngine(rules *Rules, secret string) *Engine {
+	return &Engine{rules: rules, fpSecret: []byte(secret)}
+}
+
+// Register 追加自定义规则，在内置规则之后执行
+func (e *Engine) Register(rule Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.extra = append(e.extra, rule)
+}
+
+// Rules 返回当前生效的规则配置
+func (e *Engine) Rules() *Rules {
+	return e.rules
+}
+
+// NewInput 由支付请求构建风控输入，卡号只用于计算指纹和BIN
+func (e *Engine) NewInput(req *models.PaymentRequest) *Input {
+	in := &Input{
+		MerchantTransID: req.MerchantTransID,
+		Amount:          req.Amount,
+		Currency:        strings.ToUpper(req.Currency),
+		BillingCountry:  strings.ToUpper(req.BillingCountry),
+		IP:              req.ClientIP,
+		Email:           strings.ToLower(strings.TrimSpace(req.CustomerEmail)),
+		Now:             time.Now(),
+	}
+	if req.CardInfo != nil {
+		pan := req.CardInfo.CardNumber
+		mac := hmac.New(sha256.New, e.fpSecret)
+		mac.Write([]byte(pan))
+		in.CardFingerprint = "fp_" + hex.EncodeToString(mac.Sum(nil)[:16])
+		if len(pan) >= 10 {
+			in.CardBIN = pan[:6]
+			in.CardLast4 = pan[len(pan)-4:]
+		}
+	}
+	return in
+}
+
+// Evaluate 执行所有规则，保存评估结果后返回
+// 黑名单命中直接拒绝，白名单命中时跳过其余规则
+func (e *Engine) Evaluate(in *Input) (models.RiskAssessment, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	assessment := models.RiskAssessment{
+		ID:              in.MerchantTransID,
+		MerchantTransID: in.MerchantTransID,
+		Decision:        models.RiskAllow,
+		Amount:          in.Amount,
+		Currency:        in.Currency,
+		CardFingerprint: in.CardFingerprint,
+		CardBIN:         in.CardBIN,
+		CardLast4:       in.CardLast4,
+		BINCountry:      e.rules.binCountry(in.CardBIN),
+		BillingCountry:  in.BillingCountry,
+		IP:              in.IP,
+		Email:           in.Email,
+		CreatedAt:       in.Now,
+	}
+
+	var rules []Rule
+	if reason, ok := e.rules.BlockList.match(in, assessment.BINCountry); ok {
+		addResult(&assessment, models.RiskRuleResult{Rule: "block_list", Decision: models.RiskBlock, Reason: reason})
+	} else if reason, ok := e.rules.AllowList.match(in, assessment.BINCountry); ok {
+		addResult(&assessment, models.RiskRuleResult{Rule: "allow_list", Decision: models.RiskAllow, Reason: reason})
+	} else {
+		rules = append(e.rules.builtin(), e.extra...)
+	}
+
+	for _, rule := range rules {
+		if result := rule.Evaluate(in); result != nil {
+			if result.Rule == "" {
+				result.Rule = rule.Name()
+			}
+			addResult(&assessment, *result)
+		}
+	}
+
+	if err := Assessments().Put(assessment.ID, assessment); err != nil {
+		return assessment, fmt.Errorf("failed to save risk assessment: %w", err)
+	}
+	return assessment, nil
+}
+
+// Assessments 风控评估记录
+func Assessments() *store.Collection[models.RiskAssessment] {
+	return store.Open[models.RiskAssessment]("risk_assessments")
+}
+
+// severity 决策的严重程度
+func severity(decision string) int {
+	switch decision {
+	case models.RiskBlock:
+		return 2
+	case models.RiskReview:
+		return 1
+	default:
+		return 0
+	}
+}
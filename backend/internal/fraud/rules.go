@@ -0,0 +1,274 @@
+package fraud
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"payment-demo/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultRules []byte
+
+// 频率规则的统计维度
+const (
+	VelocityCard  = "card"
+	VelocityIP    = "ip"
+	VelocityEmail = "email"
+)
+
+// VelocityRule 时间窗口内同一维度的尝试次数达到review/block时给出对应决策，0表示不启用该级别
+type VelocityRule struct {
+	Key    string        `yaml:"key" json:"key"`
+	Window time.Duration `yaml:"window" json:"window"`
+	Review int           `yaml:"review" json:"review"`
+	Block  int           `yaml:"block" json:"block"`
+}
+
+// AmountThreshold 单笔金额阈值（主币种单位），0表示不启用该级别
+type AmountThreshold struct {
+	Review float64 `yaml:"review" json:"review"`
+	Block  float64 `yaml:"block" json:"block"`
+}
+
+// List 黑/白名单
+type List struct {
+	Cards     []string `yaml:"cards" json:"cards"`
+	BINs      []string `yaml:"bins" json:"bins"`
+	IPs       []string `yaml:"ips" json:"ips"`
+	Emails    []string `yaml:"emails" json:"emails"`
+	Countries []string `yaml:"countries" json:"countries"`
+}
+
+// Rules 风控规则配置
+type Rules struct {
+	Velocity           []VelocityRule             `yaml:"velocity" json:"velocity"`
+	BINCountryMismatch string                     `yaml:"binCountryMismatch" json:"binCountryMismatch"`
+	BINCountries       map[string]string          `yaml:"binCountries" json:"binCountries"`
+	AmountThresholds   map[string]AmountThreshold `yaml:"amountThresholds" json:"amountThresholds"`
+	BlockList          List                       `yaml:"blockList" json:"blockList"`
+	AllowList          List                       `yaml:"allowList" json:"allowList"`
+}
+
+// LoadRules 从YAML/JSON文件加载规则，path为空时使用内置规则
+func LoadRules(path string) (*Rules, error) {
+	if path == "" {
+		return ParseRules(defaultRules)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fraud rules: %w", err)
+	}
+	return ParseRules(data)
+}
+
+// ParseRules 解析并校验规则
+func ParseRules(data []byte) (*Rules, error) {
+	var r Rules
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse fraud rules: %w", err)
+	}
+	for _, v := range r.Velocity {
+		if v.Key != VelocityCard && v.Key != VelocityIP && v.Key != VelocityEmail {
+			return nil, fmt.Errorf("fraud rules: unknown velocity key %q", v.Key)
+		}
+		if v.Window <= 0 {
+			return nil, fmt.Errorf("fraud rules: velocity %s window must be positive", v.Key)
+		}
+	}
+	switch r.BINCountryMismatch {
+	case "", models.RiskReview, models.RiskBlock:
+	default:
+		return nil, fmt.Errorf("fraud rules: binCountryMismatch must be review or block")
+	}
+	for _, cidr := range append(r.BlockList.IPs, r.AllowList.IPs...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil && net.ParseIP(cidr) == nil {
+			return nil, fmt.Errorf("fraud rules: invalid ip %q", cidr)
+		}
+	}
+	thresholds := make(map[string]AmountThreshold, len(r.AmountThresholds))
+	for code, t := range r.AmountThresholds {
+		thresholds[strings.ToUpper(code)] = t
+	}
+	r.AmountThresholds = thresholds
+	return &r, nil
+}
+
+// builtin 按配置生成内置规则
+func (r *Rules) builtin() []Rule {
+	rules := make([]Rule, 0, len(r.Velocity)+2)
+	for _, v := range r.Velocity {
+		rules = append(rules, velocityRule{v})
+	}
+	if r.BINCountryMismatch != "" {
+		rules = append(rules, binCountryRule{rules: r})
+	}
+	rules = append(rules, amountRule{thresholds: r.AmountThresholds})
+	return rules
+}
+
+// binCountry 按最长前缀查找BIN所属国家
+func (r *Rules) binCountry(bin string) string {
+	for n := len(bin); n > 0; n-- {
+		if country, ok := r.BINCountries[bin[:n]]; ok {
+			return strings.ToUpper(country)
+		}
+	}
+	return ""
+}
+
+// match 返回名单命中的原因
+func (l List) match(in *Input, binCountry string) (string, bool) {
+	for _, card := range l.Cards {
+		if in.CardFingerprint != "" && card == in.CardFingerprint {
+			return "card " + card, true
+		}
+	}
+	for _, bin := range l.BINs {
+		if in.CardBIN != "" && strings.HasPrefix(in.CardBIN, bin) {
+			return "bin " + bin, true
+		}
+	}
+	if ip := net.ParseIP(in.IP); ip != nil {
+		for _, entry := range l.IPs {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(ip) {
+				return "ip " + entry, true
+			}
+			if entryIP := net.ParseIP(entry); entryIP != nil && entryIP.Equal(ip) {
+				return "ip " + entry, true
+			}
+		}
+	}
+	if in.Email != "" {
+		for _, entry := range l.Emails {
+			entry = strings.ToLower(entry)
+			if entry == in.Email || (strings.HasPrefix(entry, "@") && strings.HasSuffix(in.Email, entry)) {
+				return "email " + entry, true
+			}
+		}
+	}
+	for _, country := range l.Countries {
+		if strings.EqualFold(country, in.BillingCountry) || strings.EqualFold(country, binCountry) {
+			return "country " + strings.ToUpper(country), true
+		}
+	}
+	return "", false
+}
+
+// velocityRule 统计窗口内同一卡/IP/邮箱的历史评估次数（加上本次）
+type velocityRule struct {
+	VelocityRule
+}
+
+func (r velocityRule) Name() string {
+	return "velocity_" + r.Key
+}
+
+func (r velocityRule) Evaluate(in *Input) *models.RiskRuleResult {
+	value := r.value(in)
+	if value == "" {
+		return nil
+	}
+	since := in.Now.Add(-r.Window)
+	previous := Assessments().Filter(func(a models.RiskAssessment) bool {
+		return a.ID != in.MerchantTransID && a.CreatedAt.After(since) && r.value(&Input{
+			CardFingerprint: a.CardFingerprint,
+			IP:              a.IP,
+			Email:           a.Email,
+		}) == value
+	})
+	attempts := len(previous) + 1
+
+	decision := ""
+	switch {
+	case r.Block > 0 && attempts >= r.Block:
+		decision = models.RiskBlock
+	case r.Review > 0 && attempts >= r.Review:
+		decision = models.RiskReview
+	default:
+		return nil
+	}
+	return &models.RiskRuleResult{
+		Decision: decision,
+		Reason:   fmt.Sprintf("%d attempts from same %s within %s", attempts, r.Key, r.Window),
+	}
+}
+
+func (r velocityRule) value(in *Input) string {
+	switch r.Key {
+	case VelocityCard:
+		return in.CardFingerprint
+	case VelocityIP:
+		return in.IP
+	case VelocityEmail:
+		return in.Email
+	}
+	return ""
+}
+
+// binCountryRule 卡BIN所属国家与账单国家不一致
+type binCountryRule struct {
+	rules *Rules
+}
+
+func (r binCountryRule) Name() string {
+	return "bin_country_mismatch"
+}
+
+func (r binCountryRule) Evaluate(in *Input) *models.RiskRuleResult {
+	binCountry := r.rules.binCountry(in.CardBIN)
+	if binCountry == "" || in.BillingCountry == "" || binCountry == in.BillingCountry {
+		return nil
+	}
+	return &models.RiskRuleResult{
+		Decision: r.rules.BINCountryMismatch,
+		Reason:   fmt.Sprintf("card issued in %s, billing country %s", binCountry, in.BillingCountry),
+	}
+}
+
+// amountRule 单笔金额超过币种阈值
+type amountRule struct {
+	thresholds map[string]AmountThreshold
+}
+
+func (r amountRule) Name() string {
+	return "amount_threshold"
+}
+
+func (r amountRule) Evaluate(in *Input) *models.RiskRuleResult {
+	t, ok := r.thresholds[in.Currency]
+	if !ok {
+		return nil
+	}
+	switch {
+	case t.Block > 0 && in.Amount >= t.Block:
+		return &models.RiskRuleResult{Decision: models.RiskBlock, Reason: fmt.Sprintf("amount %g %s reaches block threshold %g", in.Amount, in.Currency, t.Block)}
+	case t.Review > 0 && in.Amount >= t.Review:
+		return &models.RiskRuleResult{Decision: models.RiskReview, Reason: fmt.Sprintf("amount %g %s reaches review threshold %g", in.Amount, in.Currency, t.Review)}
+	}
+	return nil
+}
+
+// addResult 记录命中结果并升级决策
+func addResult(a *models.RiskAssessment, result models.RiskRuleResult) {
+	a.Rules = append(a.Rules, result)
+	if severity(result.Decision) > severity(a.Decision) {
+		a.Decision = result.Decision
+	}
+}
+
+// MarshalJSON 窗口以 "10m0s" 形式输出
+func (v VelocityRule) MarshalJSON() ([]byte, error) {
+	type plain VelocityRule
+	return json.Marshal(struct {
+		plain
+		Window string `json:"window"`
+	}{plain(v), v.Window.String()})
+}
@@ -0,0 +1,181 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+var (
+	// ErrQuoteExpired 报价已过有效期
+	ErrQuoteExpired = errors.New("fx quote has expired")
+	// ErrQuoteUsed 报价已被其他支付使用
+	ErrQuoteUsed = errors.New("fx quote has already been used")
+	// ErrQuoteMismatch 支付的币种或金额与报价不一致
+	ErrQuoteMismatch = errors.New("payment does not match fx quote")
+)
+
+// Conversion 一次换算的结果
+type Conversion struct {
+	From      string
+	To        string
+	Amount    float64
+	Converted float64 // 按目标币种小数位舍入
+	Rate      float64
+	Source    string
+	AsOf      time.Time
+}
+
+// Convert 按当前汇率将amount从from换算为to，并按to币种的小数位和舍入方式舍入
+func Convert(amount float64, from, to string) (Conversion, error) {
+	cur, ok := catalog.Current().Currency(to)
+	if !ok {
+		return Conversion{}, fmt.Errorf("%w: %s", catalog.ErrUnsupportedCurrency, to)
+	}
+	p := Current()
+	table, err := p.Rates()
+	if err != nil {
+		return Conversion{}, err
+	}
+	rate, err := table.Rate(from, to)
+	if err != nil {
+		return Conversion{}, err
+	}
+	return Conversion{
+		From:      strings.ToUpper(from),
+		To:        cur.Code,
+		Amount:    amount,
+		Converted: cur.Round(amount*rate, config.Load().FXRounding),
+		Rate:      rate,
+		Source:    p.Name(),
+		AsOf:      table.AsOf,
+	}, nil
+}
+
+// Rate 按当前汇率返回1单位from可兑换的to数量
+func Rate(from, to string) (float64, error) {
+	table, err := Current().Rates()
+	if err != nil {
+		return 0, err
+	}
+	return table.Rate(from, to)
+}
+
+// Quotes 汇率报价集合
+func Quotes() *store.Collection[models.FXQuote] {
+	return store.Open[models.FXQuote]("fx_quotes")
+}
+
+// CreateQuote 按当前汇率生成报价并锁定FX_QUOTE_TTL，换算后的金额需在支付币种的金额范围内
+func CreateQuote(req *models.FXQuoteRequest) (models.FXQuote, error) {
+	cfg := config.Load()
+	base := strings.ToUpper(req.BaseCurrency)
+	if base == "" {
+		base = cfg.FXBaseCurrency
+	}
+
+	conv, err := Convert(req.Amount, base, req.Currency)
+	if err != nil {
+		return models.FXQuote{}, err
+	}
+	if err := catalog.Current().ValidateAmount(conv.To, conv.Converted); err != nil {
+		return models.FXQuote{}, err
+	}
+
+	now := time.Now()
+	quote := models.FXQuote{
+		ID:           utils.GenerateID("fxq"),
+		Status:       models.FXQuoteOpen,
+		BaseCurrency: conv.From,
+		BaseAmount:   req.Amount,
+		Currency:     conv.To,
+		Amount:       conv.Converted,
+		Rate:         conv.Rate,
+		Source:       conv.Source,
+		RatesAsOf:    conv.AsOf,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(cfg.FXQuoteTTL),
+	}
+	if err := Quotes().Insert(quote.ID, quote); err != nil {
+		return models.FXQuote{}, fmt.Errorf("failed to save fx quote: %w", err)
+	}
+	return quote, nil
+}
+
+// GetQuote 查询报价并计算当前状态
+func GetQuote(id string) (models.FXQuote, bool) {
+	quote, ok := Quotes().Get(id)
+	if ok {
+		quote.Status = quoteStatus(quote, time.Now())
+	}
+	return quote, ok
+}
+
+// CheckQuote 校验报价可用于该支付：未过期、未被使用，币种和金额与报价一致
+func CheckQuote(id, currency string, amount float64) (models.FXQuote, error) {
+	quote, ok := GetQuote(id)
+	if !ok {
+		return quote, fmt.Errorf("fx quote %s: %w", id, store.ErrNotFound)
+	}
+	return quote, checkQuote(quote, currency, amount)
+}
+
+func checkQuote(quote models.FXQuote, currency string, amount float64) error {
+	switch quoteStatus(quote, time.Now()) {
+	case models.FXQuoteUsed:
+		return fmt.Errorf("%w by %s", ErrQuoteUsed, quote.MerchantTransID)
+	case models.FXQuoteExpired:
+		return fmt.Errorf("%w at %s", ErrQuoteExpired, quote.ExpiresAt.Format(time.RFC3339))
+	}
+	if !strings.EqualFold(currency, quote.Currency) || amount != quote.Amount {
+		return fmt.Errorf("%w: quote is %g %s", ErrQuoteMismatch, quote.Amount, quote.Currency)
+	}
+	return nil
+}
+
+// Redeem 将报价标记为已被该支付使用，与校验在同一次更新中完成，避免并发重复使用
+func Redeem(id, merchantTransID, currency string, amount float64) (models.FXQuote, error) {
+	return Quotes().Update(id, func(q *models.FXQuote) error {
+		if err := checkQuote(*q, currency, amount); err != nil {
+			return err
+		}
+		now := time.Now()
+		q.Status = models.FXQuoteUsed
+		q.MerchantTransID = merchantTransID
+		q.UsedAt = &now
+		return nil
+	})
+}
+
+// Release 支付未能创建时释放报价，过期前可再次使用
+func Release(id, merchantTransID string) {
+	_, err := Quotes().Update(id, func(q *models.FXQuote) error {
+		if q.MerchantTransID == merchantTransID {
+			q.Status = models.FXQuoteOpen
+			q.MerchantTransID = ""
+			q.UsedAt = nil
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("[FX] 释放报价失败 - quoteID: %s, error: %v\n", id, err)
+	}
+}
+
+func quoteStatus(q models.FXQuote, now time.Time) string {
+	switch {
+	case q.MerchantTransID != "":
+		return models.FXQuoteUsed
+	case !now.Before(q.ExpiresAt):
+		return models.FXQuoteExpired
+	default:
+		return models.FXQuoteOpen
+	}
+}
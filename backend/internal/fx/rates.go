@@ -0,0 +1,196 @@
+// Package fx 汇率表、币种换算和锁定报价
+package fx
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultRates []byte
+
+// ErrNoRate 汇率表中没有该币种
+var ErrNoRate = errors.New("no exchange rate")
+
+// RateTable 以base为基准的汇率表，非基础币种之间通过base交叉换算
+type RateTable struct {
+	Base  string             `yaml:"base"`
+	AsOf  time.Time          `yaml:"asOf"`
+	Rates map[string]float64 `yaml:"rates"` // 1单位base可兑换的各币种数量
+}
+
+// Provider 汇率来源
+type Provider interface {
+	Name() string
+	Rates() (*RateTable, error)
+}
+
+// ParseRates 解析并校验汇率表（YAML或JSON）
+func ParseRates(data []byte) (*RateTable, error) {
+	var t RateTable
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse fx rates: %w", err)
+	}
+	if err := t.normalize(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// normalize 币种代码转为大写、校验汇率为正，并补上base自身的汇率1
+func (t *RateTable) normalize() error {
+	t.Base = strings.ToUpper(t.Base)
+	if t.Base == "" {
+		return errors.New("fx rates: base currency is required")
+	}
+	rates := make(map[string]float64, len(t.Rates)+1)
+	for code, rate := range t.Rates {
+		if rate <= 0 {
+			return fmt.Errorf("fx rates: rate for %s must be positive", code)
+		}
+		rates[strings.ToUpper(code)] = rate
+	}
+	if r, ok := rates[t.Base]; ok && r != 1 {
+		return fmt.Errorf("fx rates: base currency %s must have rate 1", t.Base)
+	}
+	rates[t.Base] = 1
+	t.Rates = rates
+	return nil
+}
+
+// Rate 返回1单位from可兑换的to数量
+func (t *RateTable) Rate(from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	fromRate, ok := t.Rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w for %s", ErrNoRate, from)
+	}
+	toRate, ok := t.Rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w for %s", ErrNoRate, to)
+	}
+	return toRate / fromRate, nil
+}
+
+// StaticProvider 固定的内存汇率表，用于内置汇率和测试
+type StaticProvider struct {
+	table *RateTable
+}
+
+// NewStaticProvider 使用给定汇率创建，rates中缺少base时自动补1
+func NewStaticProvider(base string, rates map[string]float64, asOf time.Time) (*StaticProvider, error) {
+	table := &RateTable{Base: base, AsOf: asOf, Rates: rates}
+	if err := table.normalize(); err != nil {
+		return nil, err
+	}
+	return &StaticProvider{table: table}, nil
+}
+
+func (p *StaticProvider) Name() string { return "static" }
+
+func (p *StaticProvider) Rates() (*RateTable, error) { return p.table, nil }
+
+// FileProvider 从文件读取汇率，文件修改时间变化后重新解析
+type FileProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	table   *RateTable
+}
+
+// NewFileProvider 创建文件汇率来源，首次调用Rates时读取
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+func (p *FileProvider) Name() string { return "file:" + p.path }
+
+// Rates 文件有误时继续使用上次成功解析的汇率表
+func (p *FileProvider) Rates() (*RateTable, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		if p.table != nil {
+			fmt.Printf("[FX] 读取汇率文件失败，继续使用上次的汇率: %v\n", err)
+			return p.table, nil
+		}
+		return nil, fmt.Errorf("failed to read fx rates: %w", err)
+	}
+	if p.table != nil && info.ModTime().Equal(p.modTime) {
+		return p.table, nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err == nil {
+		var table *RateTable
+		if table, err = ParseRates(data); err == nil {
+			if table.AsOf.IsZero() {
+				table.AsOf = info.ModTime()
+			}
+			p.table, p.modTime = table, info.ModTime()
+			return table, nil
+		}
+	}
+	if p.table != nil {
+		fmt.Printf("[FX] 解析汇率文件失败，继续使用上次的汇率: %v\n", err)
+		return p.table, nil
+	}
+	return nil, err
+}
+
+var (
+	providerMu sync.RWMutex
+	provider   Provider
+)
+
+// Current 返回当前汇率来源：配置了FX_RATES_PATH时读取文件，否则使用内置静态汇率
+func Current() Provider {
+	providerMu.RLock()
+	p := provider
+	providerMu.RUnlock()
+	if p != nil {
+		return p
+	}
+
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if provider == nil {
+		if path := config.Load().FXRatesPath; path != "" {
+			provider = NewFileProvider(path)
+		} else {
+			table, _ := ParseRates(defaultRates)
+			provider = &StaticProvider{table: table}
+		}
+	}
+	return provider
+}
+
+// SetProvider 替换汇率来源
+func SetProvider(p Provider) {
+	providerMu.Lock()
+	provider = p
+	providerMu.Unlock()
+}
+
+// CurrentRates 当前汇率表
+func CurrentRates() (models.FXRates, error) {
+	p := Current()
+	table, err := p.Rates()
+	if err != nil {
+		return models.FXRates{}, err
+	}
+	return models.FXRates{Base: table.Base, Rates: table.Rates, Source: p.Name(), AsOf: table.AsOf}, nil
+}
@@ -0,0 +1,152 @@
+// Package health 维护服务的存活与就绪状态
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 组件及整体状态
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"    // 非关键组件异常，仍可服务
+	StatusUnavailable = "unavailable" // 关键组件异常
+	StatusDraining    = "draining"    // 停机排空中
+)
+
+var draining atomic.Bool
+
+// SetDraining 标记服务正在停机排空，此后就绪检查返回未就绪
+func SetDraining() {
+	draining.Store(true)
+}
+
+// Draining 服务是否正在停机排空
+func Draining() bool {
+	return draining.Load()
+}
+
+// CheckFunc 组件检查，返回nil表示正常
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+	details  func() interface{}
+}
+
+var (
+	checksMu sync.RWMutex
+	checks   []check
+)
+
+// Register 注册就绪检查；critical为true时该组件异常会使就绪检查返回503
+func Register(name string, critical bool, fn CheckFunc) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks = append(checks, check{name: name, critical: critical, fn: fn})
+}
+
+// RegisterWithDetails 注册就绪检查，details的返回值附加到该组件的结果中
+func RegisterWithDetails(name string, critical bool, fn CheckFunc, details func() interface{}) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks = append(checks, check{name: name, critical: critical, fn: fn, details: details})
+}
+
+// Component 单个组件的检查结果
+type Component struct {
+	Status     string      `json:"status"`
+	Critical   bool        `json:"critical"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"durationMs"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// Report 整体检查结果
+type Report struct {
+	Status     string               `json:"status"`
+	Components map[string]Component `json:"components"`
+	CheckedAt  time.Time            `json:"checkedAt"`
+}
+
+// Healthy 整体状态是否可接收流量
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK || r.Status == StatusDegraded
+}
+
+// Ready 并发执行所有已注册检查及后台任务存活检查
+func Ready(ctx context.Context) Report {
+	checksMu.RLock()
+	all := append([]check(nil), checks...)
+	checksMu.RUnlock()
+	all = append(all, check{name: "workers", critical: true, fn: checkWorkers})
+
+	report := Report{Status: StatusOK, Components: make(map[string]Component, len(all)), CheckedAt: time.Now()}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range all {
+		wg.Add(1)
+		go func(c check) {
+			defer wg.Done()
+			component := runCheck(ctx, c)
+			mu.Lock()
+			report.Components[c.name] = component
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	report.Status = overallStatus(report.Components)
+	if Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// Live 存活检查：仅检查后台任务是否仍在运行，异常时应重启进程
+func Live(ctx context.Context) Report {
+	report := Report{Status: StatusOK, CheckedAt: time.Now()}
+	report.Components = map[string]Component{
+		"workers": runCheck(ctx, check{name: "workers", critical: true, fn: checkWorkers}),
+	}
+	report.Status = overallStatus(report.Components)
+	return report
+}
+
+func runCheck(ctx context.Context, c check) Component {
+	start := time.Now()
+	err := c.fn(ctx)
+	component := Component{Status: StatusUp, Critical: c.critical, DurationMs: time.Since(start).Milliseconds()}
+	if c.name == "workers" {
+		component.Details = WorkerStates()
+	}
+	if c.details != nil {
+		component.Details = c.details()
+	}
+	if err != nil {
+		component.Status = StatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
+
+func overallStatus(components map[string]Component) string {
+	status := StatusOK
+	for _, c := range components {
+		if c.Status == StatusUp {
+			continue
+		}
+		if c.Critical {
+			return StatusUnavailable
+		}
+		status = StatusDegraded
+	}
+	return status
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 心跳超过 staleFactor 个间隔再加 staleGrace 未更新即视为卡死
+const (
+	staleFactor = 3
+	staleGrace  = time.Minute
+)
+
+type workerState struct {
+	interval time.Duration
+	lastBeat time.Time
+	stopped  bool
+}
+
+var (
+	workersMu sync.Mutex
+	workers   = map[string]*workerState{}
+)
+
+// WorkerState 后台任务状态
+type WorkerState struct {
+	Name     string    `json:"name"`
+	Interval string    `json:"interval"`
+	LastBeat time.Time `json:"lastBeat"`
+	Stopped  bool      `json:"stopped,omitempty"`
+	Stale    bool      `json:"stale,omitempty"`
+}
+
+// RegisterWorker 登记以interval为周期运行的后台任务
+func RegisterWorker(name string, interval time.Duration) {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	workers[name] = &workerState{interval: interval, lastBeat: time.Now()}
+}
+
+// Beat 后台任务每轮循环调用一次，表明仍在运行
+func Beat(name string) {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	if w, ok := workers[name]; ok {
+		w.lastBeat = time.Now()
+	}
+}
+
+// WorkerStopped 后台任务退出时调用
+func WorkerStopped(name string) {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	if w, ok := workers[name]; ok {
+		w.stopped = true
+	}
+}
+
+// WorkerStates 返回所有后台任务的状态
+func WorkerStates() []WorkerState {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+
+	now := time.Now()
+	states := make([]WorkerState, 0, len(workers))
+	for name, w := range workers {
+		states = append(states, WorkerState{
+			Name:     name,
+			Interval: w.interval.String(),
+			LastBeat: w.lastBeat,
+			Stopped:  w.stopped,
+			Stale:    !w.stopped && now.Sub(w.lastBeat) > staleFactor*w.interval+staleGrace,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// checkWorkers 有任务已退出或心跳超时时返回错误
+func checkWorkers(context.Context) error {
+	var problems []string
+	for _, w := range WorkerStates() {
+		switch {
+		case w.Stopped:
+			problems = append(problems, w.Name+" stopped")
+		case w.Stale:
+			problems = append(problems, fmt.Sprintf("%s last beat %s ago", w.Name, time.Since(w.LastBeat).Round(time.Second)))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
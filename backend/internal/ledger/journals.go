@@ -0,0 +1,231 @@
+package ledger
+
+import (
+	"fmt"
+	"time"
+
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+)
+
+// 各业务事件的记账规则（借方为正）：
+//
+//	扣款     借 cash A             贷 revenue A；借 fees F 贷 cash F
+//	退款成功 借 refunds R          贷 cash R；借 fees f 贷 cash f（退还的手续费f为负）
+//	拒付     借 dispute_reserve D  贷 cash D
+//	  胜诉   借 cash D             贷 dispute_reserve D
+//	  败诉   借 chargebacks D      贷 dispute_reserve D（接受拒付同败诉）
+//	付款     借 payouts_in_transit P 贷 cash P
+//	  到账   借 payouts P          贷 payouts_in_transit P
+//	  失败   借 cash P             贷 payouts_in_transit P
+//	  退回   借 cash P             贷 payouts P
+
+// CaptureEntry 支付扣款及其手续费的分录
+func CaptureEntry(p models.PaymentRecord, at time.Time) models.JournalEntry {
+	postings := []models.Posting{
+		{Account: AccountCash, Amount: p.Amount},
+		{Account: AccountRevenue, Amount: -p.Amount},
+	}
+	if p.Fees != nil {
+		postings = append(postings,
+			models.Posting{Account: AccountFees, Amount: p.Fees.Total},
+			models.Posting{Account: AccountCash, Amount: -p.Fees.Total},
+		)
+	}
+	return models.JournalEntry{
+		ID:          models.JournalCapture + ":" + p.MerchantTransID,
+		Type:        models.JournalCapture,
+		Currency:    p.Currency,
+		Environment: p.Environment,
+		SourceType:  "payment",
+		SourceID:    p.MerchantTransID,
+		Postings:    postings,
+		PostedAt:    at,
+	}
+}
+
+// RefundEntry 退款及其手续费的分录
+func RefundEntry(r models.Refund, at time.Time) models.JournalEntry {
+	postings := []models.Posting{
+		{Account: AccountRefunds, Amount: r.Amount},
+		{Account: AccountCash, Amount: -r.Amount},
+	}
+	if r.Fees != nil {
+		postings = append(postings,
+			models.Posting{Account: AccountFees, Amount: r.Fees.Total},
+			models.Posting{Account: AccountCash, Amount: -r.Fees.Total},
+		)
+	}
+	return models.JournalEntry{
+		ID:          models.JournalRefund + ":" + r.RefundID,
+		Type:        models.JournalRefund,
+		Currency:    r.Currency,
+		Environment: r.Environment,
+		SourceType:  "refund",
+		SourceID:    r.RefundID,
+		Description: "refund of " + r.MerchantTransID,
+		Postings:    postings,
+		PostedAt:    at,
+	}
+}
+
+// DisputeEntries 拒付当前状态对应的分录：冻结资金，结案后释放或计入拒付损失
+// 一笔拒付只结案一次，accepted之后再变为lost不会重复记账
+func DisputeEntries(d models.Dispute, openedAt, closedAt time.Time) []models.JournalEntry {
+	entry := func(typ string, debit, credit string, at time.Time) models.JournalEntry {
+		return models.JournalEntry{
+			ID:          typ + ":" + d.ID,
+			Type:        typ,
+			Currency:    d.Currency,
+			Environment: d.Environment,
+			SourceType:  "dispute",
+			SourceID:    d.ID,
+			Description: fmt.Sprintf("dispute %s on %s", d.Status, d.MerchantTransID),
+			Postings: []models.Posting{
+				{Account: debit, Amount: d.Amount},
+				{Account: credit, Amount: -d.Amount},
+			},
+			PostedAt: at,
+		}
+	}
+
+	entries := []models.JournalEntry{entry(models.JournalDisputeOpened, AccountDisputeReserve, AccountCash, openedAt)}
+	switch d.Status {
+	case models.DisputeWon:
+		entries = append(entries, entry(models.JournalDisputeClosed, AccountCash, AccountDisputeReserve, closedAt))
+	case models.DisputeLost, models.DisputeAccepted:
+		entries = append(entries, entry(models.JournalDisputeClosed, AccountChargebacks, AccountDisputeReserve, closedAt))
+	}
+	return entries
+}
+
+// PayoutEntries 付款当前状态对应的分录（含之前各状态的分录）
+func PayoutEntries(p models.Payout, createdAt, completedAt time.Time) []models.JournalEntry {
+	entry := func(typ string, debit, credit string, at time.Time) models.JournalEntry {
+		return models.JournalEntry{
+			ID:          typ + ":" + p.ID,
+			Type:        typ,
+			Currency:    p.Currency,
+			Environment: p.Environment,
+			SourceType:  "payout",
+			SourceID:    p.ID,
+			Description: "payout to " + p.BeneficiaryID,
+			Postings: []models.Posting{
+				{Account: debit, Amount: p.Amount},
+				{Account: credit, Amount: -p.Amount},
+			},
+			PostedAt: at,
+		}
+	}
+
+	entries := []models.JournalEntry{entry(models.JournalPayoutCreated, AccountPayoutsInTransit, AccountCash, createdAt)}
+	switch p.Status {
+	case models.PayoutPaid:
+		entries = append(entries, entry(models.JournalPayoutPaid, AccountPayouts, AccountPayoutsInTransit, completedAt))
+	case models.PayoutFailed:
+		entries = append(entries, entry(models.JournalPayoutFailed, AccountCash, AccountPayoutsInTransit, completedAt))
+	case models.PayoutReturned:
+		entries = append(entries,
+			entry(models.JournalPayoutPaid, AccountPayouts, AccountPayoutsInTransit, completedAt),
+			entry(models.JournalPayoutReturned, AccountCash, AccountPayouts, completedAt),
+		)
+	}
+	return entries
+}
+
+// 以下Record*在业务状态变化后记账，返回记账失败的错误；
+// 漏记的分录可由Verify对照业务记录发现，并通过Backfill补记
+
+// RecordCapture 支付变为captured时记账
+func RecordCapture(p models.PaymentRecord) error {
+	return record(CaptureEntry(p, time.Now()))
+}
+
+// RecordRefund 退款成功后记账
+func RecordRefund(r models.Refund) error {
+	return record(RefundEntry(r, time.Now()))
+}
+
+// RecordDispute 拒付创建或状态变化后记账，已记过的分录会被跳过
+func RecordDispute(d models.Dispute) error {
+	now := time.Now()
+	for _, e := range DisputeEntries(d, now, now) {
+		if err := record(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordPayout 付款创建或状态变化后记账，已记过的分录会被跳过
+func RecordPayout(p models.Payout) error {
+	now := time.Now()
+	for _, e := range PayoutEntries(p, now, now) {
+		if err := record(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func record(e models.JournalEntry) error {
+	if _, err := Post(e); err != nil {
+		fmt.Printf("[Ledger] 记账失败，可通过补记修复 - entry: %s, error: %v\n", e.ID, err)
+		return fmt.Errorf("failed to post %s: %w", e.ID, err)
+	}
+	return nil
+}
+
+// Backfill 为启用记账前已发生或记账失败的业务补记分录，记账时间取业务记录上的时间；已记过的分录会被跳过
+// 返回新写入的分录数
+func Backfill(payouts []models.Payout, disputes []models.Dispute) (int, error) {
+	posted := 0
+	for _, e := range Expected(payouts, disputes) {
+		ok, err := Post(e)
+		if err != nil {
+			return posted, err
+		}
+		if ok {
+			posted++
+		}
+	}
+	return posted, nil
+}
+
+// Expected 按业务记录（已扣款的支付、成功的退款、拒付和付款）生成账本中应有的全部分录
+func Expected(payouts []models.Payout, disputes []models.Dispute) []models.JournalEntry {
+	var entries []models.JournalEntry
+	for _, p := range store.Payments().List() {
+		if p.Status == models.StatusCaptured || p.Status == models.StatusPartiallyRefunded || p.Status == models.StatusRefunded {
+			entries = append(entries, CaptureEntry(p, capturedAt(p)))
+		}
+	}
+	for _, r := range store.Refunds().List() {
+		if r.Status == models.RefundSucceeded {
+			entries = append(entries, RefundEntry(r, r.UpdatedAt))
+		}
+	}
+	for _, d := range disputes {
+		closedAt := d.UpdatedAt
+		if d.ClosedAt != nil {
+			closedAt = *d.ClosedAt
+		}
+		entries = append(entries, DisputeEntries(d, d.CreatedAt, closedAt)...)
+	}
+	for _, p := range payouts {
+		completedAt := p.UpdatedAt
+		if p.CompletedAt != nil {
+			completedAt = *p.CompletedAt
+		}
+		entries = append(entries, PayoutEntries(p, p.CreatedAt, completedAt)...)
+	}
+	return entries
+}
+
+// capturedAt 早期记录没有capturedAt，以创建时间代替
+func capturedAt(p models.PaymentRecord) time.Time {
+	if p.CapturedAt != nil {
+		return *p.CapturedAt
+	}
+	return p.CreatedAt
+}
@@ -0,0 +1,268 @@
+// Package ledger 复式记账：扣款、退款、手续费、拒付和付款按币种记入各账户，
+// 每笔分录借贷平衡，可查询任意时点的账户余额并校验全部分录合计为零
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+)
+
+// 账户
+const (
+	AccountCash             = "cash"               // 存放在Evonet、可结算给商户的资金
+	AccountDisputeReserve   = "dispute_reserve"    // 因拒付被冻结的资金
+	AccountPayoutsInTransit = "payouts_in_transit" // 已提交尚未到账的付款
+	AccountRevenue          = "revenue"            // 扣款收入
+	AccountRefunds          = "refunds"            // 退款（收入的抵减）
+	AccountFees             = "fees"               // 支付与退款手续费
+	AccountChargebacks      = "chargebacks"        // 拒付损失
+	AccountPayouts          = "payouts"            // 已付给收款人的资金
+)
+
+// 账户类型
+const (
+	TypeAsset         = "asset"
+	TypeRevenue       = "revenue"
+	TypeContraRevenue = "contra_revenue"
+	TypeExpense       = "expense"
+	TypeEquity        = "equity"
+)
+
+// Accounts 账户表：账户 -> 类型
+var Accounts = map[string]string{
+	AccountCash:             TypeAsset,
+	AccountDisputeReserve:   TypeAsset,
+	AccountPayoutsInTransit: TypeAsset,
+	AccountRevenue:          TypeRevenue,
+	AccountRefunds:          TypeContraRevenue,
+	AccountFees:             TypeExpense,
+	AccountChargebacks:      TypeExpense,
+	AccountPayouts:          TypeEquity,
+}
+
+// ErrUnbalanced 分录借贷不平衡
+var ErrUnbalanced = errors.New("journal entry does not balance")
+
+// Entries 日记账分录集合
+func Entries() *store.Collection[models.JournalEntry] {
+	return store.Open[models.JournalEntry]("ledger_entries")
+}
+
+// Post 校验并写入分录；同ID的分录已存在时不重复记账，返回false
+func Post(entry models.JournalEntry) (bool, error) {
+	if entry.PostedAt.IsZero() {
+		entry.PostedAt = time.Now()
+	}
+	postings := entry.Postings[:0]
+	for _, p := range entry.Postings {
+		if _, ok := Accounts[p.Account]; !ok {
+			return false, fmt.Errorf("unknown ledger account: %s", p.Account)
+		}
+		p.Amount = round(entry.Currency, p.Amount)
+		if p.Amount != 0 {
+			postings = append(postings, p)
+		}
+	}
+	entry.Postings = postings
+	if len(entry.Postings) == 0 {
+		return false, nil
+	}
+	if sum := minorSum(entry.Currency, entry.Postings); sum != 0 {
+		return false, fmt.Errorf("%w: %s off by %d minor units", ErrUnbalanced, entry.ID, sum)
+	}
+
+	err := Entries().Insert(entry.ID, entry)
+	if errors.Is(err, store.ErrDuplicate) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Query 分录与余额的查询条件，零值字段不参与过滤
+type Query struct {
+	Environment string
+	Currency    string
+	Account     string
+	SourceID    string
+	From        time.Time // 含
+	To          time.Time // 不含
+}
+
+func (q Query) matches(e models.JournalEntry) bool {
+	if (q.Environment != "" && e.Environment != q.Environment) ||
+		(q.Currency != "" && e.Currency != q.Currency) ||
+		(q.SourceID != "" && e.SourceID != q.SourceID) ||
+		(!q.From.IsZero() && e.PostedAt.Before(q.From)) ||
+		(!q.To.IsZero() && !e.PostedAt.Before(q.To)) {
+		return false
+	}
+	if q.Account == "" {
+		return true
+	}
+	for _, p := range e.Postings {
+		if p.Account == q.Account {
+			return true
+		}
+	}
+	return false
+}
+
+// List 按记账时间排序返回符合条件的分录
+func List(q Query) []models.JournalEntry {
+	entries := Entries().Filter(q.matches)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].PostedAt.Equal(entries[j].PostedAt) {
+			return entries[i].PostedAt.Before(entries[j].PostedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// Balances 返回at之前（不含）已记账的各账户余额，at为零值时为当前余额
+// 按环境、币种、账户排序，借方余额为正
+func Balances(q Query, at time.Time) []models.LedgerBalance {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	q.From, q.To = time.Time{}, at
+
+	type key struct{ env, currency, account string }
+	sums := map[key]float64{}
+	for _, e := range Entries().Filter(q.matches) {
+		for _, p := range e.Postings {
+			if q.Account == "" || p.Account == q.Account {
+				sums[key{e.Environment, e.Currency, p.Account}] += p.Amount
+			}
+		}
+	}
+
+	balances := make([]models.LedgerBalance, 0, len(sums))
+	for k, sum := range sums {
+		balances = append(balances, models.LedgerBalance{
+			Account:     k.account,
+			AccountType: Accounts[k.account],
+			Currency:    k.currency,
+			Environment: k.env,
+			Balance:     round(k.currency, sum),
+			AsOf:        at,
+		})
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		a, b := balances[i], balances[j]
+		if a.Environment != b.Environment {
+			return a.Environment < b.Environment
+		}
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		return a.Account < b.Account
+	})
+	return balances
+}
+
+// Verification 分录校验结果
+type Verification struct {
+	Valid      bool               `json:"valid"`
+	Entries    int                `json:"entries"`
+	Unbalanced []string           `json:"unbalanced,omitempty"` // 借贷不平衡的分录ID
+	Missing    []string           `json:"missing,omitempty"`    // 业务记录应有但账本中没有的分录ID
+	Mismatched []string           `json:"mismatched,omitempty"` // 账户或金额与业务记录不一致的分录ID
+	Totals     map[string]float64 `json:"totals"`               // 各币种全部记账行的合计，应为0
+}
+
+// Verify 校验每笔分录借贷平衡、账户有效，且各币种全部分录合计为零；
+// 同时对照expected（见Expected）检查漏记和金额不一致的分录
+func Verify(expected []models.JournalEntry) Verification {
+	entries := List(Query{})
+	result := Verification{Valid: true, Entries: len(entries), Totals: map[string]float64{}}
+	posted := make(map[string]models.JournalEntry, len(entries))
+	minor := map[string]int64{}
+	for _, e := range entries {
+		posted[e.ID] = e
+		valid := true
+		for _, p := range e.Postings {
+			if _, ok := Accounts[p.Account]; !ok {
+				valid = false
+			}
+		}
+		sum := minorSum(e.Currency, e.Postings)
+		if sum != 0 || !valid {
+			result.Valid = false
+			result.Unbalanced = append(result.Unbalanced, e.ID)
+		}
+		minor[e.Currency] += sum
+	}
+	for currency, sum := range minor {
+		result.Totals[currency] = float64(sum) / math.Pow10(exponent(currency))
+		if sum != 0 {
+			result.Valid = false
+		}
+	}
+
+	for _, want := range expected {
+		got, ok := posted[want.ID]
+		switch {
+		case !ok:
+			result.Valid = false
+			result.Missing = append(result.Missing, want.ID)
+		case got.Currency != want.Currency || got.Environment != want.Environment ||
+			!samePostings(want.Currency, got.Postings, want.Postings):
+			result.Valid = false
+			result.Mismatched = append(result.Mismatched, want.ID)
+		}
+	}
+	return result
+}
+
+// samePostings 按账户比较两组记账行在最小单位上的合计
+func samePostings(currency string, a, b []models.Posting) bool {
+	diff := map[string]int64{}
+	for _, p := range a {
+		diff[p.Account] += minorSum(currency, []models.Posting{p})
+	}
+	for _, p := range b {
+		diff[p.Account] -= minorSum(currency, []models.Posting{p})
+	}
+	for _, d := range diff {
+		if d != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// minorSum 按币种最小单位合计记账行，避免浮点误差
+func minorSum(currency string, postings []models.Posting) int64 {
+	scale := math.Pow10(exponent(currency))
+	var sum int64
+	for _, p := range postings {
+		sum += int64(math.Round(p.Amount * scale))
+	}
+	return sum
+}
+
+func exponent(currency string) int {
+	if cur, ok := catalog.Current().Currency(currency); ok {
+		return cur.Exponent
+	}
+	return 2
+}
+
+func round(currency string, amount float64) float64 {
+	if cur, ok := catalog.Current().Currency(currency); ok {
+		return cur.Round(amount, config.Load().FXRounding)
+	}
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 审计日志条目，Hash = sha256(PrevHash + 本条目其余字段的JSON)，形成哈希链
+type AuditEntry struct {
+	Seq        int64           `json:"seq"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Actor      string          `json:"actor"` // admin, api_key:<指纹>, anonymous, system:<来源>
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType,omitempty"`
+	TargetID   string          `json:"targetId,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	SourceIP   string          `json:"sourceIp,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	PrevHash   string          `json:"prevHash"`
+	Hash       string          `json:"hash"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Evonet推送的拒付相关eventCode
+const (
+	EventDisputeOpened  = "DISPUTE_OPENED"
+	EventDisputeUpdated = "DISPUTE_UPDATED"
+	EventDisputeWon     = "DISPUTE_WON"
+	EventDisputeLost    = "DISPUTE_LOST"
+)
+
+// 拒付状态
+const (
+	DisputeNeedsResponse = "needs_response" // 等待商户应诉或接受
+	DisputeUnderReview   = "under_review"   // 已提交证据，等待发卡行裁决
+	DisputeAccepted      = "accepted"       // 商户接受拒付，不再应诉
+	DisputeWon           = "won"
+	DisputeLost          = "lost"
+)
+
+// 商户对拒付的处理方式
+const (
+	DisputeResponseAccept    = "accept"
+	DisputeResponseChallenge = "challenge"
+)
+
+// webhook中的拒付信息
+type DisputeNotification struct {
+	DisputeID       string     `json:"disputeId"`
+	MerchantTransID string     `json:"merchantTransId,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	ReasonCode      string     `json:"reasonCode,omitempty"`
+	Amount          float64    `json:"amount,omitempty"`
+	Currency        string     `json:"currency,omitempty"`
+	EvidenceDueBy   *time.Time `json:"evidenceDueBy,omitempty"`
+}
+
+// 拒付记录，ID为Evonet的disputeId
+type Dispute struct {
+	ID              string            `json:"id"`
+	MerchantTransID string            `json:"merchantTransId"`
+	Status          string            `json:"status"`
+	Reason          string            `json:"reason,omitempty"`
+	ReasonCode      string            `json:"reasonCode,omitempty"`
+	Amount          float64           `json:"amount"`
+	Currency        string            `json:"currency"`
+	EvidenceDueBy   *time.Time        `json:"evidenceDueBy,omitempty"`
+	Evidence        []DisputeEvidence `json:"evidence"`
+	Response        string            `json:"response,omitempty"` // accept, challenge
+	ResponseNote    string            `json:"responseNote,omitempty"`
+	RespondedAt     *time.Time        `json:"respondedAt,omitempty"`
+	ClosedAt        *time.Time        `json:"closedAt,omitempty"`
+	Environment     string            `json:"environment,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+// 拒付证据文件，内容保存在本地证据目录
+type DisputeEvidence struct {
+	ID          string    `json:"id"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	Description string    `json:"description,omitempty"`
+	UploadedAt  time.Time `json:"uploadedAt"`
+}
+
+// 商户应诉或接受拒付
+type DisputeResponseRequest struct {
+	Action string `json:"action" binding:"required,oneof=accept challenge"`
+	Note   string `json:"note" binding:"max=2000"`
+}
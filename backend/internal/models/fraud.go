@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// 风控决策，严重程度依次递增
+const (
+	RiskAllow  = "allow"
+	RiskReview = "review" // 只记录：照常发送到Evonet，由人工事后复核
+	RiskBlock  = "block"  // 拒绝，不发送到Evonet
+)
+
+// 单条规则的命中结果
+type RiskRuleResult struct {
+	Rule     string `json:"rule"`
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// Direct API支付的风控评估，ID与merchantTransId相同
+// 只保存卡指纹、BIN和末四位，不保存完整卡号
+type RiskAssessment struct {
+	ID              string           `json:"id"`
+	MerchantTransID string           `json:"merchantTransId"`
+	Decision        string           `json:"decision"`
+	Rules           []RiskRuleResult `json:"rules,omitempty"`
+	Amount          float64          `json:"amount"`
+	Currency        string           `json:"currency"`
+	CardFingerprint string           `json:"cardFingerprint"`
+	CardBIN         string           `json:"cardBin"`
+	CardLast4       string           `json:"cardLast4"`
+	BINCountry      string           `json:"binCountry,omitempty"`
+	BillingCountry  string           `json:"billingCountry,omitempty"`
+	IP              string           `json:"ip,omitempty"`
+	Email           string           `json:"email,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// 汇率报价状态
+const (
+	FXQuoteOpen    = "open"
+	FXQuoteUsed    = "used"
+	FXQuoteExpired = "expired"
+)
+
+// 汇率报价请求：将基础币种金额换算为顾客支付的币种
+type FXQuoteRequest struct {
+	Amount       float64 `json:"amount" binding:"required,gt=0"`                                    // 基础币种金额
+	BaseCurrency string  `json:"baseCurrency,omitempty" binding:"omitempty,len=3,catalog_currency"` // 为空时使用FX_BASE_CURRENCY
+	Currency     string  `json:"currency" binding:"required,len=3,catalog_currency"`                // 支付币种
+}
+
+// 锁定的汇率报价，在有效期内创建支付时使用报价中的金额和汇率
+type FXQuote struct {
+	ID              string     `json:"id"`
+	Status          string     `json:"status"` // 查询时根据使用情况和有效期计算
+	BaseCurrency    string     `json:"baseCurrency"`
+	BaseAmount      float64    `json:"baseAmount"`
+	Currency        string     `json:"currency"`
+	Amount          float64    `json:"amount"` // 按支付币种小数位舍入后的金额
+	Rate            float64    `json:"rate"`   // 1单位基础币种 = rate单位支付币种
+	Source          string     `json:"source"`
+	RatesAsOf       time.Time  `json:"ratesAsOf"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	ExpiresAt       time.Time  `json:"expiresAt"`
+	MerchantTransID string     `json:"merchantTransId,omitempty"`
+	UsedAt          *time.Time `json:"usedAt,omitempty"`
+}
+
+// 汇率表
+type FXRates struct {
+	Base   string             `json:"base"`
+	Rates  map[string]float64 `json:"rates"` // 1单位base可兑换的各币种数量
+	Source string             `json:"source"`
+	AsOf   time.Time          `json:"asOf"`
+}
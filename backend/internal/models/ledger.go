@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// 分录类型
+const (
+	JournalCapture        = "capture"
+	JournalRefund         = "refund"
+	JournalDisputeOpened  = "dispute.opened"
+	JournalDisputeClosed  = "dispute.closed"
+	JournalPayoutCreated  = "payout.created"
+	JournalPayoutPaid     = "payout.paid"
+	JournalPayoutFailed   = "payout.failed"
+	JournalPayoutReturned = "payout.returned"
+)
+
+// 记账行：金额为正表示借方，为负表示贷方
+type Posting struct {
+	Account string  `json:"account"`
+	Amount  float64 `json:"amount"`
+}
+
+// 日记账分录，同一分录的记账行币种相同且合计为零
+type JournalEntry struct {
+	ID          string    `json:"id"` // <type>:<sourceId>，同一业务事件只记一次
+	Type        string    `json:"type"`
+	Currency    string    `json:"currency"`
+	Environment string    `json:"environment"`
+	SourceType  string    `json:"sourceType"` // payment, refund, dispute, payout
+	SourceID    string    `json:"sourceId"`
+	Description string    `json:"description,omitempty"`
+	Postings    []Posting `json:"postings"`
+	PostedAt    time.Time `json:"postedAt"`
+}
+
+// 账户在某一时点的余额，借方为正
+type LedgerBalance struct {
+	Account     string    `json:"account"`
+	AccountType string    `json:"accountType"`
+	Currency    string    `json:"currency"`
+	Environment string    `json:"environment"`
+	Balance     float64   `json:"balance"`
+	AsOf        time.Time `json:"asOf"`
+}
@@ -4,41 +4,59 @@ import "time"
 
 // 国家和币种配置
 type Country struct {
-	Code     string `json:"code"`
-	Name     string `json:"name"`
-	Currency string `json:"currency"`
-	Language string `json:"language"`
+	Code           string              `json:"code"`
+	Name           string              `json:"name"`
+	Currency       string              `json:"currency"`
+	Language       string              `json:"language"`
+	PaymentMethods map[string][]string `json:"paymentMethods,omitempty"` // 集成方式 -> 支付方式
 }
 
 // 支付场景
 type PaymentScenario struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Environment string `json:"environment"` // UAT, Production
-	Type        string `json:"type"`        // linkpay, dropin, directapi
-	Description string `json:"description"`
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Environment string `json:"environment" yaml:"environment"` // UAT, Production
+	Type        string `json:"type" yaml:"type"`               // linkpay, dropin, directapi
+	Description string `json:"description" yaml:"description"`
 }
 
 // 支付请求
+// 自定义校验规则见 internal/api/validation.go
 type PaymentRequest struct {
-	Amount          float64 `json:"amount"`
-	Currency        string  `json:"currency"`
-	MerchantTransID string  `json:"merchantTransId"`
-	PaymentType     string  `json:"paymentType"`
-	PaymentMethod   string  `json:"paymentMethod,omitempty"`
-	ReturnURL       string  `json:"returnUrl"`
-	WebhookURL      string  `json:"webhookUrl"`
-	
+	Amount          float64 `json:"amount" binding:"required,gt=0"`
+	Currency        string  `json:"currency" binding:"required,len=3,catalog_currency"`
+	MerchantTransID string  `json:"merchantTransId" binding:"omitempty,merchant_trans_id"` // 为空时由服务端生成
+	PaymentType     string  `json:"paymentType" binding:"required,oneof=linkpay dropin directapi"`
+	PaymentMethod   string  `json:"paymentMethod,omitempty" binding:"omitempty,max=32"`
+	ReturnURL       string  `json:"returnUrl" binding:"required,allowed_url"`
+	WebhookURL      string  `json:"webhookUrl" binding:"omitempty,allowed_url"`
+
+	// 风控使用的客户信息（Direct API）
+	CustomerEmail  string `json:"customerEmail,omitempty" binding:"omitempty,email,max=254"`
+	BillingCountry string `json:"billingCountry,omitempty" binding:"omitempty,len=2,alpha"`
+	ClientIP       string `json:"-"` // 由接口层填入请求来源IP
+
+	PaymentLinkID string `json:"-"` // 通过支付链接发起时由服务端填入
+
+	// 订单明细，提供时合计必须等于amount
+	Order *Order `json:"order,omitempty" binding:"omitempty"`
+
+	// 锁定的汇率报价ID，使用时currency和amount必须与报价一致
+	FXQuoteID string `json:"fxQuoteId,omitempty" binding:"max=64"`
+
+	// 商户自定义数据（购物车ID、用户ID等），最多20个键，原样返回并可在列表中过滤
+	Metadata map[string]string `json:"metadata,omitempty" binding:"max=20,dive,keys,min=1,max=40,metadata_key,endkeys,max=500"`
+
 	// 卡片信息（Direct API）
-	CardInfo *CardInfo `json:"cardInfo,omitempty"`
+	CardInfo *CardInfo `json:"cardInfo,omitempty" binding:"omitempty"`
 }
 
 // 卡片信息
 type CardInfo struct {
-	CardNumber  string `json:"cardNumber"`
-	ExpiryDate  string `json:"expiryDate"`
-	CVV         string `json:"cvv"`
-	HolderName  string `json:"holderName"`
+	CardNumber string `json:"cardNumber" binding:"required,numeric,min=12,max=19"`
+	ExpiryDate string `json:"expiryDate" binding:"required,numeric,len=4"` // MMYY
+	CVV        string `json:"cvv" binding:"omitempty,numeric,min=3,max=4"`
+	HolderName string `json:"holderName" binding:"max=64"`
 }
 
 // 支付响应
@@ -61,18 +79,120 @@ type ActionInfo struct {
 
 // Webhook通知
 type WebhookNotification struct {
-	EventCode string    `json:"eventCode"`
-	Payment   *Payment  `json:"payment,omitempty"`
-	Timestamp time.Time `json:"timestamp"`
+	EventID   string               `json:"eventId,omitempty"`
+	EventCode string               `json:"eventCode"`
+	Payment   *Payment             `json:"payment,omitempty"`
+	Dispute   *DisputeNotification `json:"dispute,omitempty"`
+	Timestamp time.Time            `json:"timestamp"`
 }
 
 // 支付信息
 type Payment struct {
+	MerchantTransID string            `json:"merchantTransId"`
+	Status          string            `json:"status"`
+	Amount          float64           `json:"amount"`
+	Currency        string            `json:"currency"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	Metadata        map[string]string `json:"metadata,omitempty"` // 来自本地支付记录
+}
+
+// 支付状态
+const (
+	StatusPending    = "pending"
+	StatusAuthorized = "authorized"
+	StatusCaptured   = "captured"
+	StatusFailed     = "failed"
+	StatusCancelled  = "cancelled"
+	StatusExpired    = "expired"
+	StatusUnknown    = "unknown"
+
+	StatusPartiallyRefunded = "partially_refunded"
+	StatusRefunded          = "refunded"
+)
+
+// 支付类型
+const (
+	PaymentTypeLinkPay   = "linkpay"
+	PaymentTypeDropIn    = "dropin"
+	PaymentTypeDirectAPI = "directapi"
+)
+
+// 本地保存的支付记录
+type PaymentRecord struct {
+	MerchantTransID string            `json:"merchantTransId"`
+	PaymentType     string            `json:"paymentType"` // linkpay, dropin, directapi
+	PaymentMethod   string            `json:"paymentMethod,omitempty"`
+	Status          string            `json:"status"`
+	Amount          float64           `json:"amount"`
+	Currency        string            `json:"currency"`
+	RefundedAmount  float64           `json:"refundedAmount,omitempty"`
+	Environment     string            `json:"environment"` // sandbox, production
+	SessionID       string            `json:"sessionId,omitempty"`
+	LinkURL         string            `json:"linkUrl,omitempty"`
+	RiskDecision    string            `json:"riskDecision,omitempty"` // Direct API风控决策：allow, review, block
+	PaymentLinkID   string            `json:"paymentLinkId,omitempty"`
+	Order           *Order            `json:"order,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	// 已发起、结果未知的退款预留的金额（refundedAmount只含成功的退款），可退金额 = amount - refundedAmount - pendingRefundAmount
+	PendingRefundAmount float64 `json:"pendingRefundAmount,omitempty"`
+	// 基础币种（商品定价币种）金额；amount/currency为顾客支付的币种
+	BaseAmount     float64 `json:"baseAmount,omitempty"`
+	BaseCurrency   string  `json:"baseCurrency,omitempty"`
+	FXRate         float64 `json:"fxRate,omitempty"` // 1单位基础币种 = fxRate单位支付币种
+	FXQuoteID      string  `json:"fxQuoteId,omitempty"`
+	BillingCountry string  `json:"billingCountry,omitempty"`
+	// 扣款时按费率表计算的手续费、退款产生的手续费合计（退还时为负）和净额
+	Fees          *Fees      `json:"fees,omitempty"`
+	RefundFees    float64    `json:"refundFees,omitempty"`
+	NetAmount     float64    `json:"netAmount,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	CapturedAt    *time.Time `json:"capturedAt,omitempty"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+}
+
+// IsInteraction 是否为LinkPay/Drop-in交互（需通过交互接口查询状态）
+func (p *PaymentRecord) IsInteraction() bool {
+	return p.PaymentType == PaymentTypeLinkPay || p.PaymentType == PaymentTypeDropIn
+}
+
+// 退款请求
+type RefundRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// 退款状态
+const (
+	RefundPending   = "pending"
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
+)
+
+// 退款记录
+type Refund struct {
+	RefundID        string    `json:"refundId"` // 退款请求的merchantTransID
 	MerchantTransID string    `json:"merchantTransId"`
-	Status          string    `json:"status"`
 	Amount          float64   `json:"amount"`
 	Currency        string    `json:"currency"`
+	Status          string    `json:"status"`
+	Reason          string    `json:"reason,omitempty"`
+	PaymentMethod   string    `json:"paymentMethod,omitempty"`
+	Environment     string    `json:"environment"`
+	Message         string    `json:"message,omitempty"`
+	Fees            *Fees     `json:"fees,omitempty"`
 	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// 手续费明细，币种与支付相同；退款中退还的部分为负数
+type Fees struct {
+	Rule       string  `json:"rule"` // 命中的费率规则
+	Currency   string  `json:"currency"`
+	Percentage float64 `json:"percentage"`
+	Fixed      float64 `json:"fixed"`
+	FXMarkup   float64 `json:"fxMarkup"`
+	Total      float64 `json:"total"`
 }
 
 // Evonet API响应结构
@@ -102,4 +222,4 @@ type EvonetPaymentResponse struct {
 		Code    string `json:"code"`
 		Message string `json:"message"`
 	} `json:"result"`
-}
\ No newline at end of file
+}
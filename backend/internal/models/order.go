@@ -0,0 +1,56 @@
+package models
+
+// 订单信息，随支付请求提交并保存在支付记录中
+// 合计 = Σ(数量×单价 - 行折扣 + 行税费) - 订单折扣 + 订单税费 + 运费，必须等于支付金额
+type Order struct {
+	Items           []OrderItem `json:"items" binding:"required,min=1,max=100,dive"`
+	Discount        float64     `json:"discount,omitempty" binding:"gte=0"`
+	Tax             float64     `json:"tax,omitempty" binding:"gte=0"`
+	Shipping        float64     `json:"shipping,omitempty" binding:"gte=0"`
+	Buyer           *Buyer      `json:"buyer,omitempty" binding:"omitempty"`
+	ShippingAddress *Address    `json:"shippingAddress,omitempty" binding:"omitempty"`
+}
+
+// 订单行，金额单位与支付金额一致
+type OrderItem struct {
+	SKU       string  `json:"sku,omitempty" binding:"max=64"`
+	Name      string  `json:"name" binding:"required,max=128"`
+	Category  string  `json:"category,omitempty" binding:"max=64"`
+	Quantity  int     `json:"quantity" binding:"required,min=1,max=10000"`
+	UnitPrice float64 `json:"unitPrice" binding:"gte=0"`
+	Discount  float64 `json:"discount,omitempty" binding:"gte=0"` // 本行折扣合计
+	Tax       float64 `json:"tax,omitempty" binding:"gte=0"`      // 本行税费合计
+}
+
+// 买家信息
+type Buyer struct {
+	ID    string `json:"id,omitempty" binding:"max=64"` // 商户侧的客户ID
+	Name  string `json:"name,omitempty" binding:"max=128"`
+	Email string `json:"email,omitempty" binding:"omitempty,email,max=254"`
+	Phone string `json:"phone,omitempty" binding:"max=32"`
+}
+
+// 收货地址
+type Address struct {
+	Name       string `json:"name,omitempty" binding:"max=128"` // 收件人
+	Line1      string `json:"line1" binding:"required,max=128"`
+	Line2      string `json:"line2,omitempty" binding:"max=128"`
+	City       string `json:"city" binding:"required,max=64"`
+	State      string `json:"state,omitempty" binding:"max=64"`
+	PostalCode string `json:"postalCode,omitempty" binding:"max=16"`
+	Country    string `json:"country" binding:"required,len=2,alpha"`
+}
+
+// Subtotal 订单行小计（已扣行折扣、含行税费）
+func (item OrderItem) Subtotal() float64 {
+	return float64(item.Quantity)*item.UnitPrice - item.Discount + item.Tax
+}
+
+// Total 订单合计
+func (o *Order) Total() float64 {
+	total := o.Shipping + o.Tax - o.Discount
+	for _, item := range o.Items {
+		total += item.Subtotal()
+	}
+	return total
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// 支付链接状态，expired和completed由过期时间和使用次数实时计算
+const (
+	PaymentLinkActive    = "active"
+	PaymentLinkInactive  = "inactive" // 已停用
+	PaymentLinkExpired   = "expired"
+	PaymentLinkCompleted = "completed" // 使用次数已满
+)
+
+// 创建支付链接
+// amount为空时由顾客输入金额，可用minAmount/maxAmount限制范围；maxUses为0表示不限次数
+type PaymentLinkRequest struct {
+	Amount        float64           `json:"amount,omitempty" binding:"omitempty,gt=0"`
+	MinAmount     float64           `json:"minAmount,omitempty" binding:"omitempty,gt=0"`
+	MaxAmount     float64           `json:"maxAmount,omitempty" binding:"omitempty,gt=0"`
+	Currency      string            `json:"currency" binding:"required,len=3,catalog_currency"`
+	Description   string            `json:"description,omitempty" binding:"max=500"`
+	PaymentMethod string            `json:"paymentMethod,omitempty" binding:"omitempty,max=32"`
+	ReturnURL     string            `json:"returnUrl" binding:"required,allowed_url"`
+	MaxUses       int               `json:"maxUses,omitempty" binding:"min=0"`
+	ExpiresAt     *time.Time        `json:"expiresAt,omitempty"` // 为空时使用PAYMENT_LINK_DEFAULT_EXPIRY
+	Metadata      map[string]string `json:"metadata,omitempty" binding:"max=20,dive,keys,min=1,max=40,metadata_key,endkeys,max=500"`
+}
+
+// 支付链接
+type PaymentLink struct {
+	ID            string            `json:"id"`
+	URL           string            `json:"url"` // 分享给顾客的地址，打开后展示确认页，提交后跳转到Evonet LinkPay
+	Status        string            `json:"status"`
+	Active        bool              `json:"active"`
+	Amount        float64           `json:"amount,omitempty"` // 为0表示顾客输入金额
+	MinAmount     float64           `json:"minAmount,omitempty"`
+	MaxAmount     float64           `json:"maxAmount,omitempty"`
+	Currency      string            `json:"currency"`
+	Description   string            `json:"description,omitempty"`
+	PaymentMethod string            `json:"paymentMethod,omitempty"`
+	ReturnURL     string            `json:"returnUrl"`
+	MaxUses       int               `json:"maxUses"`
+	Uses          int               `json:"uses"` // 已扣款的支付数
+	ExpiresAt     *time.Time        `json:"expiresAt,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Environment   string            `json:"environment"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+	DeactivatedAt *time.Time        `json:"deactivatedAt,omitempty"`
+}
+
+// 通过支付链接发起支付，仅顾客输入金额的链接需要amount
+type PaymentLinkCheckout struct {
+	Amount float64 `json:"amount,omitempty" form:"amount" binding:"omitempty,gt=0"`
+}
@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// 收款人类型
+const (
+	BeneficiaryIndividual = "individual"
+	BeneficiaryBusiness   = "business"
+)
+
+// 付款状态
+const (
+	PayoutPending    = "pending"    // 已创建，尚未提交
+	PayoutProcessing = "processing" // 已提交，等待出款结果
+	PayoutPaid       = "paid"
+	PayoutFailed     = "failed"
+	PayoutReturned   = "returned" // 出款后被收款银行退回
+)
+
+// 收款银行账户
+type BankAccount struct {
+	AccountName   string `json:"accountName" binding:"required,max=128"`
+	AccountNumber string `json:"accountNumber" binding:"required,min=4,max=34,alphanum"` // 响应中只保留后4位
+	BankName      string `json:"bankName,omitempty" binding:"max=128"`
+	BankCode      string `json:"bankCode,omitempty" binding:"max=32"`
+	SwiftCode     string `json:"swiftCode,omitempty" binding:"omitempty,alphanum,min=8,max=11"`
+}
+
+// 创建收款人请求
+type BeneficiaryRequest struct {
+	Name        string            `json:"name" binding:"required,max=128"`
+	Type        string            `json:"type" binding:"required,oneof=individual business"`
+	Email       string            `json:"email,omitempty" binding:"omitempty,email,max=254"`
+	Country     string            `json:"country" binding:"required,len=2,alpha"`
+	Currency    string            `json:"currency" binding:"required,len=3,catalog_currency"`
+	BankAccount BankAccount       `json:"bankAccount" binding:"required"`
+	Metadata    map[string]string `json:"metadata,omitempty" binding:"max=20,dive,keys,min=1,max=40,metadata_key,endkeys,max=500"`
+}
+
+// 收款人（卖家）
+type Beneficiary struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Email       string            `json:"email,omitempty"`
+	Country     string            `json:"country"`
+	Currency    string            `json:"currency"` // 只能以该币种付款
+	BankAccount BankAccount       `json:"bankAccount"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Active      bool              `json:"active"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// Masked 返回隐藏账号的副本，用于接口响应和审计日志
+func (b Beneficiary) Masked() Beneficiary {
+	if n := len(b.BankAccount.AccountNumber); n > 4 {
+		b.BankAccount.AccountNumber = "****" + b.BankAccount.AccountNumber[n-4:]
+	}
+	return b
+}
+
+// 创建付款请求
+type PayoutRequest struct {
+	BeneficiaryID string            `json:"beneficiaryId" binding:"required,max=64"`
+	Amount        float64           `json:"amount" binding:"required,gt=0"`
+	Currency      string            `json:"currency" binding:"required,len=3,catalog_currency"`
+	Reference     string            `json:"reference,omitempty" binding:"max=64"` // 商户侧的结算单号等
+	Description   string            `json:"description,omitempty" binding:"max=256"`
+	Metadata      map[string]string `json:"metadata,omitempty" binding:"max=20,dive,keys,min=1,max=40,metadata_key,endkeys,max=500"`
+}
+
+// 付款记录
+type Payout struct {
+	ID                string            `json:"id"`
+	BeneficiaryID     string            `json:"beneficiaryId"`
+	Amount            float64           `json:"amount"`
+	Currency          string            `json:"currency"`
+	Status            string            `json:"status"`
+	Reference         string            `json:"reference,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	Provider          string            `json:"provider"`
+	ProviderReference string            `json:"providerReference,omitempty"`
+	FailureReason     string            `json:"failureReason,omitempty"`
+	Environment       string            `json:"environment"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+	SubmittedAt       *time.Time        `json:"submittedAt,omitempty"`
+	CompletedAt       *time.Time        `json:"completedAt,omitempty"` // 进入paid、failed或returned的时间
+}
+
+// 某币种的可付款余额
+// available = captured - refunded - fees - paidOut - inTransit
+type Balance struct {
+	Currency    string  `json:"currency"`
+	Environment string  `json:"environment"`
+	Captured    float64 `json:"captured"`
+	Refunded    float64 `json:"refunded"`
+	Fees        float64 `json:"fees"`
+	Disputed    float64 `json:"disputed"` // 未结、败诉及已接受的拒付
+	PaidOut     float64 `json:"paidOut"`
+	InTransit   float64 `json:"inTransit"` // pending和processing的付款
+	Cash        float64 `json:"cash"`      // 账本cash账户余额
+	Available   float64 `json:"available"`
+}
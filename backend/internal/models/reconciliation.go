@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// 对账结果分类
+const (
+	ReconMatched         = "matched"
+	ReconAmountMismatch  = "amount_mismatch"
+	ReconStatusMismatch  = "status_mismatch"  // 金额一致，但Evonet状态（如失败、撤销）与本地不符
+	ReconMissingLocally  = "missing_locally"  // Evonet文件中有，本地没有
+	ReconMissingUpstream = "missing_upstream" // 本地已结算，Evonet文件中没有
+)
+
+// 对账明细
+type ReconciliationItem struct {
+	Category        string  `json:"category"`
+	Kind            string  `json:"kind"` // payment, refund
+	MerchantTransID string  `json:"merchantTransId"`
+	Currency        string  `json:"currency,omitempty"`
+	LocalAmount     float64 `json:"localAmount,omitempty"`
+	UpstreamAmount  float64 `json:"upstreamAmount,omitempty"`
+	LocalStatus     string  `json:"localStatus,omitempty"`
+	UpstreamStatus  string  `json:"upstreamStatus,omitempty"`
+	Note            string  `json:"note,omitempty"`
+}
+
+// 对账汇总
+type ReconciliationSummary struct {
+	Matched         int `json:"matched"`
+	AmountMismatch  int `json:"amountMismatch"`
+	StatusMismatch  int `json:"statusMismatch"`
+	MissingLocally  int `json:"missingLocally"`
+	MissingUpstream int `json:"missingUpstream"`
+}
+
+// 一次对账
+type Reconciliation struct {
+	ID        string                `json:"id"`
+	FileName  string                `json:"fileName"`
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	LineCount int                   `json:"lineCount"`
+	Summary   ReconciliationSummary `json:"summary"`
+	Items     []ReconciliationItem  `json:"items,omitempty"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 商户注册的webhook接收地址
+type WebhookEndpoint struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty"`
+	Events      []string  `json:"events,omitempty"` // 为空表示订阅全部事件
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// 商户webhook事件
+type WebhookEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// 投递状态
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead" // 重试耗尽，进入死信队列
+)
+
+// 一次事件到某个endpoint的投递
+type WebhookDelivery struct {
+	ID             string       `json:"id"`
+	EndpointID     string       `json:"endpointId"`
+	Event          WebhookEvent `json:"event"`
+	Status         string       `json:"status"`
+	Attempts       int          `json:"attempts"` // 累计投递次数，重新投递不清零
+	NextAttemptAt  time.Time    `json:"nextAttemptAt"`
+	LastStatusCode int          `json:"lastStatusCode,omitempty"`
+	LastError      string       `json:"lastError,omitempty"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+	DeliveredAt    *time.Time   `json:"deliveredAt,omitempty"`
+
+	// 手动重新投递次数；重试上限和退避按本轮（创建或最近一次重新投递之后）的次数计算
+	Redeliveries  int                      `json:"redeliveries,omitempty"`
+	RoundAttempts int                      `json:"roundAttempts,omitempty"`
+	History       []WebhookDeliveryAttempt `json:"history,omitempty"` // 最近的投递尝试，按时间顺序
+}
+
+// 单次投递尝试
+type WebhookDeliveryAttempt struct {
+	Attempt    int       `json:"attempt"` // 累计第几次投递
+	Round      int       `json:"round"`   // 0为首轮，n为第n次重新投递之后
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// 入站webhook事件处理状态
+const (
+	InboundReceived  = "received"
+	InboundProcessed = "processed"
+	InboundIgnored   = "ignored"  // 无本地记录或状态已过时（乱序到达）
+	InboundRejected  = "rejected" // 签名校验未通过
+	InboundFailed    = "failed"
+)
+
+// Evonet推送的原始webhook事件
+type InboundWebhookEvent struct {
+	ID                string            `json:"id"`
+	DedupKey          string            `json:"dedupKey"`
+	EventID           string            `json:"eventId,omitempty"`
+	EventCode         string            `json:"eventCode,omitempty"`
+	MerchantTransID   string            `json:"merchantTransId,omitempty"`
+	DisputeID         string            `json:"disputeId,omitempty"`
+	Headers           map[string]string `json:"headers"`
+	Body              string            `json:"body"`
+	SignatureValid    bool              `json:"signatureValid"`
+	VerificationError string            `json:"verificationError,omitempty"`
+	Status            string            `json:"status"`
+	Result            string            `json:"result,omitempty"`
+	Attempts          int               `json:"attempts"`
+	DuplicateCount    int               `json:"duplicateCount"`
+	ReceivedAt        time.Time         `json:"receivedAt"`
+	ProcessedAt       *time.Time        `json:"processedAt,omitempty"`
+}
@@ -0,0 +1,314 @@
+// Package payout 向卖家（收款人）出款：收款人管理、按币种的可付款余额校验、付款状态机，
+// 实际出款通过Provider完成（Evonet或本地模拟器）
+package payout
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/audit"
+	"payment-demo/internal/catalog"
+	"payment-demo/internal/ledger"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+	"payment-demo/internal/webhook"
+)
+
+var (
+	// ErrInsufficientFunds 付款金额超过该币种的可付款余额
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrBeneficiaryDisabled 收款人已停用
+	ErrBeneficiaryDisabled = errors.New("beneficiary is disabled")
+	// ErrCurrencyMismatch 付款币种与收款人账户币种不一致
+	ErrCurrencyMismatch = errors.New("payout currency does not match beneficiary currency")
+	// ErrInvalidTransition 非法的付款状态流转
+	ErrInvalidTransition = errors.New("invalid payout status transition")
+)
+
+// allowedTransitions 允许的付款状态流转，paid之后仍可能被收款银行退回
+var allowedTransitions = map[string][]string{
+	models.PayoutPending:    {models.PayoutProcessing, models.PayoutPaid, models.PayoutFailed},
+	models.PayoutProcessing: {models.PayoutPaid, models.PayoutFailed},
+	models.PayoutPaid:       {models.PayoutReturned},
+}
+
+// createMu 串行化余额校验与付款记账，避免并发付款超额
+var createMu sync.Mutex
+
+// Result Provider返回的出款结果，Status为空表示状态未变化
+type Result struct {
+	Status        string
+	Reference     string // Provider侧的付款单号
+	FailureReason string
+}
+
+// Provider 出款通道
+type Provider interface {
+	Name() string
+	// Submit 提交付款，返回error表示结果未知（如超时），付款保持processing，之后通过Query确认
+	Submit(p models.Payout, b models.Beneficiary) (Result, error)
+	// Query 查询已提交付款的最新状态
+	Query(p models.Payout) (Result, error)
+}
+
+// Beneficiaries 收款人集合
+func Beneficiaries() *store.Collection[models.Beneficiary] {
+	return store.Open[models.Beneficiary]("beneficiaries")
+}
+
+// Payouts 付款记录集合
+func Payouts() *store.Collection[models.Payout] {
+	return store.Open[models.Payout]("payouts")
+}
+
+// CanTransition 判断付款状态能否从from流转到to
+func CanTransition(from, to string) bool {
+	for _, next := range allowedTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateBeneficiary 保存收款人
+func CreateBeneficiary(req *models.BeneficiaryRequest) (models.Beneficiary, error) {
+	now := time.Now()
+	b := models.Beneficiary{
+		ID:          utils.GenerateID("ben"),
+		Name:        req.Name,
+		Type:        req.Type,
+		Email:       req.Email,
+		Country:     strings.ToUpper(req.Country),
+		Currency:    strings.ToUpper(req.Currency),
+		BankAccount: req.BankAccount,
+		Metadata:    req.Metadata,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := Beneficiaries().Insert(b.ID, b); err != nil {
+		return models.Beneficiary{}, err
+	}
+	return b, nil
+}
+
+// DisableBeneficiary 停用收款人，已提交的付款不受影响
+func DisableBeneficiary(id string) (models.Beneficiary, error) {
+	return Beneficiaries().Update(id, func(b *models.Beneficiary) error {
+		b.Active = false
+		b.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// Balances 返回环境env下各币种的可付款余额，按币种排序
+// 余额取自账本：available为cash账户余额减去处理中退款的预留金额，
+// cash已扣除手续费、退款、未结及败诉的拒付和在途付款，其余字段为对应账户的余额
+func Balances(env string) []models.Balance {
+	byCurrency := map[string]*models.Balance{}
+	cash := map[string]float64{}
+	balance := func(currency string) *models.Balance {
+		if byCurrency[currency] == nil {
+			byCurrency[currency] = &models.Balance{Currency: currency, Environment: env}
+		}
+		return byCurrency[currency]
+	}
+
+	for _, lb := range ledger.Balances(ledger.Query{Environment: env}, time.Time{}) {
+		b := balance(lb.Currency)
+		switch lb.Account {
+		case ledger.AccountCash:
+			cash[lb.Currency] = lb.Balance
+		case ledger.AccountRevenue:
+			b.Captured = -lb.Balance
+		case ledger.AccountRefunds:
+			b.Refunded += lb.Balance
+		case ledger.AccountFees:
+			b.Fees = lb.Balance
+		case ledger.AccountDisputeReserve, ledger.AccountChargebacks:
+			b.Disputed += lb.Balance
+		case ledger.AccountPayouts:
+			b.PaidOut = lb.Balance
+		case ledger.AccountPayoutsInTransit:
+			b.InTransit = lb.Balance
+		}
+	}
+	// 处理中的退款尚未记账，但金额已预留，同样不可出款
+	pendingRefunds := map[string]float64{}
+	for _, p := range store.Payments().List() {
+		if p.Environment == env && p.PendingRefundAmount > 0 {
+			balance(p.Currency).Refunded += p.PendingRefundAmount
+			pendingRefunds[p.Currency] += p.PendingRefundAmount
+		}
+	}
+
+	balances := make([]models.Balance, 0, len(byCurrency))
+	for currency, b := range byCurrency {
+		b.Refunded = round(currency, b.Refunded)
+		b.Cash = cash[currency]
+		b.Available = round(currency, cash[currency]-pendingRefunds[currency])
+		balances = append(balances, *b)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Currency < balances[j].Currency
+	})
+	return balances
+}
+
+// Available 返回环境env下某币种的可付款余额
+func Available(env, currency string) float64 {
+	for _, b := range Balances(env) {
+		if b.Currency == currency {
+			return b.Available
+		}
+	}
+	return 0
+}
+
+// Create 校验收款人和余额后创建付款，并立即提交给provider
+// provider拒绝受理时付款记录保留为failed并一并返回；提交出错时无法确定对方是否已受理，
+// 付款保持processing、单号记为付款ID，由PayoutPoller查询最终状态
+func Create(req *models.PayoutRequest, env string, provider Provider) (models.Payout, error) {
+	createMu.Lock()
+	b, ok := Beneficiaries().Get(req.BeneficiaryID)
+	if !ok {
+		createMu.Unlock()
+		return models.Payout{}, fmt.Errorf("beneficiary %s: %w", req.BeneficiaryID, store.ErrNotFound)
+	}
+	currency := strings.ToUpper(req.Currency)
+	switch {
+	case !b.Active:
+		createMu.Unlock()
+		return models.Payout{}, ErrBeneficiaryDisabled
+	case b.Currency != currency:
+		createMu.Unlock()
+		return models.Payout{}, fmt.Errorf("%w: %s", ErrCurrencyMismatch, b.Currency)
+	}
+	if available := Available(env, currency); req.Amount > available {
+		createMu.Unlock()
+		return models.Payout{}, fmt.Errorf("%w: available %s %g", ErrInsufficientFunds, currency, available)
+	}
+
+	now := time.Now()
+	p := models.Payout{
+		ID:            utils.GenerateID("po"),
+		BeneficiaryID: b.ID,
+		Amount:        req.Amount,
+		Currency:      currency,
+		Status:        models.PayoutPending,
+		Reference:     req.Reference,
+		Description:   req.Description,
+		Metadata:      req.Metadata,
+		Provider:      provider.Name(),
+		Environment:   env,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := Payouts().Insert(p.ID, p); err != nil {
+		createMu.Unlock()
+		return models.Payout{}, err
+	}
+	// 可付款余额取自账本，付款分录须在释放锁之前写入
+	if err := ledger.RecordPayout(p); err != nil {
+		Payouts().Delete(p.ID)
+		createMu.Unlock()
+		return models.Payout{}, err
+	}
+	createMu.Unlock()
+	publish(p, "")
+
+	result, err := provider.Submit(p, b)
+	if err != nil {
+		fmt.Printf("[Payout] 提交付款失败 - payoutId: %s, provider: %s, error: %v\n", p.ID, p.Provider, err)
+		result = Result{Status: models.PayoutProcessing, Reference: p.ID}
+	} else if result.Status == "" {
+		result.Status = models.PayoutProcessing
+	}
+	return apply(p.ID, result, "submit", true)
+}
+
+// Refresh 向provider查询处理中的付款的最新状态
+func Refresh(id string, provider Provider) (models.Payout, error) {
+	p, ok := Payouts().Get(id)
+	if !ok {
+		return models.Payout{}, store.ErrNotFound
+	}
+	if p.Status != models.PayoutProcessing && p.Status != models.PayoutPaid {
+		return p, nil
+	}
+	result, err := provider.Query(p)
+	if err != nil {
+		return p, err
+	}
+	return apply(id, result, "query", false)
+}
+
+// apply 通过状态机写入provider结果；状态未变化时只更新单号
+func apply(id string, result Result, source string, submitted bool) (models.Payout, error) {
+	var from string
+	p, err := Payouts().Update(id, func(p *models.Payout) error {
+		from = p.Status
+		now := time.Now()
+		if result.Reference != "" {
+			p.ProviderReference = result.Reference
+		}
+		if submitted {
+			p.SubmittedAt = &now
+		}
+		if result.Status == "" || result.Status == p.Status {
+			return nil
+		}
+		if !CanTransition(p.Status, result.Status) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, p.Status, result.Status)
+		}
+		p.Status = result.Status
+		p.FailureReason = result.FailureReason
+		p.UpdatedAt = now
+		if result.Status != models.PayoutProcessing {
+			p.CompletedAt = &now
+		}
+		return nil
+	})
+	if err != nil || p.Status == from {
+		return p, err
+	}
+
+	fmt.Printf("[Payout] %s: %s -> %s (来源: %s)\n", p.ID, from, p.Status, source)
+	audit.Record(audit.Event{
+		Actor:      "system:" + source,
+		Action:     audit.ActionPayoutTransition,
+		TargetType: "payout",
+		TargetID:   p.ID,
+		Before:     map[string]string{"status": from},
+		After:      map[string]string{"status": p.Status},
+	})
+	publish(p, from)
+	return p, nil
+}
+
+// publish 记账并通知商户付款状态变化，事件类型为 payout.<status>
+func publish(p models.Payout, previousStatus string) {
+	ledger.RecordPayout(p)
+	event := map[string]interface{}{
+		"payout":         p,
+		"previousStatus": previousStatus,
+	}
+	if err := webhook.Publish("payout."+p.Status, event); err != nil {
+		fmt.Printf("[Payout] 生成商户webhook失败 - payoutId: %s, error: %v\n", p.ID, err)
+	}
+}
+
+// round 按币种小数位舍入，消除浮点累加误差
+func round(currency string, amount float64) float64 {
+	if cur, ok := catalog.Current().Currency(currency); ok {
+		return cur.Round(amount, config.Load().FXRounding)
+	}
+	return amount
+}
@@ -0,0 +1,55 @@
+package payout
+
+import (
+	"strings"
+	"time"
+
+	"payment-demo/internal/models"
+)
+
+// Simulator 本地模拟出款通道，不调用外部接口，便于演示和联调：
+//   - 账号以0000结尾：提交时即被拒绝
+//   - 账号以9999结尾：提交成功，到期后失败
+//   - 账号以7777结尾：到期后出款成功，下一次查询时被退回
+//   - 其他账号：提交后经过delay变为paid
+type Simulator struct {
+	delay time.Duration
+}
+
+// NewSimulator 创建模拟出款通道，delay为提交到出款完成的时长
+func NewSimulator(delay time.Duration) *Simulator {
+	return &Simulator{delay: delay}
+}
+
+// Name 通道名称
+func (s *Simulator) Name() string {
+	return "simulator"
+}
+
+// Submit 模拟提交付款
+func (s *Simulator) Submit(p models.Payout, b models.Beneficiary) (Result, error) {
+	if strings.HasSuffix(b.BankAccount.AccountNumber, "0000") {
+		return Result{Status: models.PayoutFailed, FailureReason: "account closed"}, nil
+	}
+	return Result{Status: models.PayoutProcessing, Reference: "sim_" + p.ID}, nil
+}
+
+// Query 模拟查询付款结果
+func (s *Simulator) Query(p models.Payout) (Result, error) {
+	if p.SubmittedAt == nil || time.Since(*p.SubmittedAt) < s.delay {
+		return Result{}, nil
+	}
+	b, _ := Beneficiaries().Get(p.BeneficiaryID)
+	account := b.BankAccount.AccountNumber
+
+	switch {
+	case p.Status == models.PayoutPaid && strings.HasSuffix(account, "7777"):
+		return Result{Status: models.PayoutReturned, FailureReason: "returned by beneficiary bank"}, nil
+	case p.Status == models.PayoutPaid:
+		return Result{}, nil
+	case strings.HasSuffix(account, "9999"):
+		return Result{Status: models.PayoutFailed, FailureReason: "rejected by beneficiary bank"}, nil
+	default:
+		return Result{Status: models.PayoutPaid}, nil
+	}
+}
@@ -0,0 +1,236 @@
+// Package qr 将LinkPay链接生成PNG或SVG二维码，可在中心叠加logo
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // logo支持JPEG
+	"image/png"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// 尺寸范围（像素）
+const (
+	MinSize     = 64
+	MaxSize     = 2048
+	DefaultSize = 256
+)
+
+// logoRatio logo边长占二维码边长的比例，需在纠错能力范围内
+const logoRatio = 0.22
+
+// ErrInvalidOptions 尺寸、纠错级别或logo参数不合法
+var ErrInvalidOptions = errors.New("invalid QR code options")
+
+// Options 生成参数
+type Options struct {
+	Size  int // 输出图片边长（像素）
+	Level qrcode.RecoveryLevel
+	Logo  image.Image // 非nil时叠加在中心
+}
+
+// ParseLevel 解析纠错级别 L/M/Q/H
+func ParseLevel(s string) (qrcode.RecoveryLevel, error) {
+	switch strings.ToUpper(s) {
+	case "L":
+		return qrcode.Low, nil
+	case "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	default:
+		return 0, fmt.Errorf("%w: error correction level must be L, M, Q or H", ErrInvalidOptions)
+	}
+}
+
+// Validate 检查尺寸；叠加logo时纠错级别至少为Q，否则遮挡部分可能无法识别
+func (o Options) Validate() error {
+	if o.Size < MinSize || o.Size > MaxSize {
+		return fmt.Errorf("%w: size must be between %d and %d", ErrInvalidOptions, MinSize, MaxSize)
+	}
+	if o.Logo != nil && o.Level < qrcode.High {
+		return fmt.Errorf("%w: logo overlay requires error correction level Q or H", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// PNG 生成PNG二维码
+func PNG(content string, opts Options) ([]byte, error) {
+	modules, err := encode(content, opts)
+	if err != nil {
+		return nil, err
+	}
+	l := newLayout(len(modules), opts.Size)
+
+	img := image.NewRGBA(image.Rect(0, 0, l.size, l.size))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				r := image.Rect(l.offset+x*l.module, l.offset+y*l.module, l.offset+(x+1)*l.module, l.offset+(y+1)*l.module)
+				draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+			}
+		}
+	}
+	if opts.Logo != nil {
+		box := l.logoBox()
+		draw.Draw(img, box, image.White, image.Point{}, draw.Src)
+		logo := scale(opts.Logo, box.Inset(l.logoPadding()).Dx())
+		draw.Draw(img, box.Inset(l.logoPadding()), logo, image.Point{}, draw.Over)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG 生成SVG二维码，相邻的深色模块合并为一段路径；logo以内嵌PNG叠加
+func SVG(content string, opts Options) ([]byte, error) {
+	modules, err := encode(content, opts)
+	if err != nil {
+		return nil, err
+	}
+	l := newLayout(len(modules), opts.Size)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		l.size, l.size, l.size, l.size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, l.size, l.size)
+	for y, row := range modules {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			run := 1
+			for x+run < len(row) && row[x+run] {
+				run++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz", l.offset+x*l.module, l.offset+y*l.module, run*l.module, l.module, run*l.module)
+			x += run
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if opts.Logo != nil {
+		box := l.logoBox()
+		inner := box.Inset(l.logoPadding())
+		var logoPNG bytes.Buffer
+		if err := png.Encode(&logoPNG, scale(opts.Logo, inner.Dx())); err != nil {
+			return nil, fmt.Errorf("failed to encode logo: %w", err)
+		}
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="#fff"/>`, box.Min.X, box.Min.Y, box.Dx(), box.Dy())
+		fmt.Fprintf(&buf, `<image x="%d" y="%d" width="%d" height="%d" href="data:image/png;base64,%s"/>`,
+			inner.Min.X, inner.Min.Y, inner.Dx(), inner.Dy(), base64.StdEncoding.EncodeToString(logoPNG.Bytes()))
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+func encode(content string, opts Options) ([][]bool, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	code, err := qrcode.New(content, opts.Level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return code.Bitmap(), nil // 含4个模块的静区
+}
+
+// layout 每个模块使用整数像素，剩余像素平均分到四周，保证模块大小一致
+type layout struct {
+	size   int
+	module int
+	offset int
+}
+
+func newLayout(modules, size int) layout {
+	if size < modules {
+		size = modules
+	}
+	module := size / modules
+	return layout{size: size, module: module, offset: (size - module*modules) / 2}
+}
+
+// logoBox logo及其白色底框所在区域
+func (l layout) logoBox() image.Rectangle {
+	side := int(float64(l.size-2*l.offset) * logoRatio)
+	start := (l.size - side) / 2
+	return image.Rect(start, start, start+side, start+side)
+}
+
+func (l layout) logoPadding() int {
+	if l.module < 2 {
+		return 1
+	}
+	return l.module / 2
+}
+
+// scale 将图片等比缩放到side×side以内（区域平均），居中放在透明画布上
+func scale(src image.Image, side int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	b := src.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 || side <= 0 {
+		return dst
+	}
+	ratio := float64(side) / float64(max(b.Dx(), b.Dy()))
+	w, h := max(1, int(float64(b.Dx())*ratio)), max(1, int(float64(b.Dy())*ratio))
+	ox, oy := (side-w)/2, (side-h)/2
+
+	for y := 0; y < h; y++ {
+		sy0, sy1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		for x := 0; x < w; x++ {
+			sx0, sx1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < max(sy1, sy0+1); sy++ {
+				for sx := sx0; sx < max(sx1, sx0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			dst.Set(ox+x, oy+y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+var (
+	logoMu    sync.Mutex
+	logoPath  string
+	logoImage image.Image
+)
+
+// LoadLogo 读取PNG/JPEG格式的logo，同一路径只解码一次
+func LoadLogo(path string) (image.Image, error) {
+	logoMu.Lock()
+	defer logoMu.Unlock()
+	if path == logoPath && logoImage != nil {
+		return logoImage, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open logo: %w", err)
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode logo: %w", err)
+	}
+	logoPath, logoImage = path, img
+	return img, nil
+}
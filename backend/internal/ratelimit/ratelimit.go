@@ -0,0 +1,60 @@
+// Package ratelimit 提供令牌桶限流，存储后端可替换
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidLimit 限流配置格式错误
+var ErrInvalidLimit = errors.New("invalid rate limit")
+
+// Limit 每个Period内最多Requests次请求，允许一次性突发Requests次
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit 解析 "10/1m" 形式的限流配置
+func ParseLimit(s string) (Limit, error) {
+	n, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%w: %q, expected <requests>/<period>", ErrInvalidLimit, s)
+	}
+	requests, err := strconv.Atoi(n)
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("%w: %q, requests must be a positive integer", ErrInvalidLimit, s)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("%w: %q, period must be a positive duration", ErrInvalidLimit, s)
+	}
+	return Limit{Requests: requests, Period: d}, nil
+}
+
+// String 返回 "10/1m0s" 形式
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// interval 补充一个令牌所需时间
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result 一次取令牌的结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // 令牌桶补满所需时间
+	RetryAfter time.Duration // 被拒绝时下一个令牌可用前的等待时间
+}
+
+// Store 令牌桶存储，内存实现见MemoryStore，多实例部署时可替换为共享存储
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (Result, error)
+}
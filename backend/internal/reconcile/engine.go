@@ -0,0 +1,237 @@
+package reconcile
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+// amountTolerance 金额比较容差（浮点误差）
+const amountTolerance = 0.005
+
+// Results 对账结果集合
+func Results() *store.Collection[models.Reconciliation] {
+	return store.Open[models.Reconciliation]("reconciliations")
+}
+
+// Run 将结算文件行与本地支付、退款按merchantTransID匹配并保存结果
+// from/to为零值时使用文件中交易日期的范围；文件没有日期列时比对全部本地记录
+func Run(fileName string, lines []Line, from, to time.Time) (*models.Reconciliation, error) {
+	if from.IsZero() && to.IsZero() {
+		from, to = dateRange(lines)
+	}
+	inRange := func(t time.Time) bool {
+		return (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to))
+	}
+
+	result := &models.Reconciliation{
+		ID:        utils.GenerateID("recon"),
+		FileName:  fileName,
+		From:      from,
+		To:        to,
+		LineCount: len(lines),
+		CreatedAt: time.Now(),
+	}
+
+	seenPayments := map[string]bool{}
+	seenRefunds := map[string]bool{}
+	for _, line := range lines {
+		var item models.ReconciliationItem
+		if line.Kind == "refund" {
+			seenRefunds[line.MerchantTransID] = true
+			item = matchRefund(line)
+		} else {
+			seenPayments[line.MerchantTransID] = true
+			item = matchPayment(line)
+		}
+		addItem(result, item)
+	}
+
+	// 本地已结算但文件中没有的记录
+	for _, p := range store.Payments().List() {
+		if seenPayments[p.MerchantTransID] || !isSettled(p.Status) || !inRange(p.CreatedAt) {
+			continue
+		}
+		addItem(result, models.ReconciliationItem{
+			Category:        models.ReconMissingUpstream,
+			Kind:            "payment",
+			MerchantTransID: p.MerchantTransID,
+			Currency:        p.Currency,
+			LocalAmount:     p.Amount,
+			LocalStatus:     p.Status,
+		})
+	}
+	for _, r := range store.Refunds().List() {
+		if seenRefunds[r.RefundID] || r.Status != models.RefundSucceeded || !inRange(r.CreatedAt) {
+			continue
+		}
+		addItem(result, models.ReconciliationItem{
+			Category:        models.ReconMissingUpstream,
+			Kind:            "refund",
+			MerchantTransID: r.RefundID,
+			Currency:        r.Currency,
+			LocalAmount:     r.Amount,
+			LocalStatus:     r.Status,
+		})
+	}
+
+	if err := Results().Insert(result.ID, *result); err != nil {
+		return nil, fmt.Errorf("failed to save reconciliation: %w", err)
+	}
+	return result, nil
+}
+
+func matchPayment(line Line) models.ReconciliationItem {
+	item := models.ReconciliationItem{
+		Kind:            "payment",
+		MerchantTransID: line.MerchantTransID,
+		Currency:        line.Currency,
+		UpstreamAmount:  line.Amount,
+		UpstreamStatus:  line.Status,
+	}
+	p, ok := store.Payments().Get(line.MerchantTransID)
+	if !ok {
+		item.Category = models.ReconMissingLocally
+		return item
+	}
+	item.LocalAmount = p.Amount
+	item.LocalStatus = p.Status
+	item.Category, item.Note = compare(p.Amount, p.Currency, line)
+	if item.Category == models.ReconMatched {
+		item.Category, item.Note = compareStatus(paymentStatusGroup(p.Status), upstreamPaymentStatus(line.Status), line.Status)
+	}
+	return item
+}
+
+func matchRefund(line Line) models.ReconciliationItem {
+	item := models.ReconciliationItem{
+		Kind:            "refund",
+		MerchantTransID: line.MerchantTransID,
+		Currency:        line.Currency,
+		UpstreamAmount:  line.Amount,
+		UpstreamStatus:  line.Status,
+	}
+	r, ok := store.Refunds().Get(line.MerchantTransID)
+	if !ok {
+		item.Category = models.ReconMissingLocally
+		return item
+	}
+	item.LocalAmount = r.Amount
+	item.LocalStatus = r.Status
+	item.Category, item.Note = compare(r.Amount, r.Currency, line)
+	if item.Category == models.ReconMatched {
+		item.Category, item.Note = compareStatus(r.Status, upstreamRefundStatus(line.Status), line.Status)
+	}
+	return item
+}
+
+// compare 比较金额和币种，文件未提供币种时只比较金额
+func compare(localAmount float64, localCurrency string, line Line) (string, string) {
+	if line.Currency != "" && !strings.EqualFold(line.Currency, localCurrency) {
+		return models.ReconAmountMismatch, fmt.Sprintf("currency differs: local %s, upstream %s", localCurrency, line.Currency)
+	}
+	// 退款在结算文件中可能以负数表示
+	if math.Abs(math.Abs(line.Amount)-localAmount) > amountTolerance {
+		return models.ReconAmountMismatch, fmt.Sprintf("amount differs by %.2f", math.Abs(line.Amount)-localAmount)
+	}
+	return models.ReconMatched, ""
+}
+
+// compareStatus 比较映射后的状态，文件未提供状态或状态无法识别时不比较
+func compareStatus(local, upstream, raw string) (string, string) {
+	if upstream == "" || upstream == local {
+		return models.ReconMatched, ""
+	}
+	return models.ReconStatusMismatch, fmt.Sprintf("status differs: local %s, upstream %s", local, raw)
+}
+
+// paymentStatusGroup 本地支付状态归类：已结算（含部分/全额退款）视为captured，authorized视为pending
+func paymentStatusGroup(status string) string {
+	switch {
+	case isSettled(status):
+		return models.StatusCaptured
+	case status == models.StatusAuthorized:
+		return models.StatusPending
+	default:
+		return status
+	}
+}
+
+// upstreamPaymentStatus 将结算文件中的支付状态映射为本地状态分组，无法识别时返回空
+func upstreamPaymentStatus(status string) string {
+	switch strings.ToLower(strings.NewReplacer("_", "", " ", "", "-", "").Replace(status)) {
+	case "success", "succeeded", "completed", "paid", "captured", "settled", "refunded", "partiallyrefunded":
+		return models.StatusCaptured
+	case "pending", "processing", "authorized", "authorised":
+		return models.StatusPending
+	case "failed", "failure", "declined", "rejected", "error":
+		return models.StatusFailed
+	case "cancelled", "canceled", "voided", "void", "reversed", "reversal":
+		return models.StatusCancelled
+	default:
+		return ""
+	}
+}
+
+// upstreamRefundStatus 将结算文件中的退款状态映射为本地退款状态，无法识别时返回空
+func upstreamRefundStatus(status string) string {
+	switch strings.ToLower(strings.NewReplacer("_", "", " ", "", "-", "").Replace(status)) {
+	case "success", "succeeded", "completed", "refunded", "settled":
+		return models.RefundSucceeded
+	case "pending", "processing":
+		return models.RefundPending
+	case "failed", "failure", "declined", "rejected", "error", "reversed", "cancelled", "canceled":
+		return models.RefundFailed
+	default:
+		return ""
+	}
+}
+
+// addItem 追加明细并更新汇总
+func addItem(result *models.Reconciliation, item models.ReconciliationItem) {
+	switch item.Category {
+	case models.ReconMatched:
+		result.Summary.Matched++
+	case models.ReconAmountMismatch:
+		result.Summary.AmountMismatch++
+	case models.ReconStatusMismatch:
+		result.Summary.StatusMismatch++
+	case models.ReconMissingLocally:
+		result.Summary.MissingLocally++
+	case models.ReconMissingUpstream:
+		result.Summary.MissingUpstream++
+	}
+	result.Items = append(result.Items, item)
+}
+
+// isSettled 本地状态是否应出现在结算文件中
+func isSettled(status string) bool {
+	return status == models.StatusCaptured || status == models.StatusPartiallyRefunded || status == models.StatusRefunded
+}
+
+// dateRange 返回文件中交易日期覆盖的自然日范围[from, to)
+func dateRange(lines []Line) (time.Time, time.Time) {
+	var from, to time.Time
+	for _, line := range lines {
+		if line.Date.IsZero() {
+			continue
+		}
+		if from.IsZero() || line.Date.Before(from) {
+			from = line.Date
+		}
+		if to.IsZero() || line.Date.After(to) {
+			to = line.Date
+		}
+	}
+	if from.IsZero() {
+		return from, to
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()).AddDate(0, 0, 1)
+	return from, to
+}
@@ -0,0 +1,124 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFile 结算文件格式不正确
+var ErrInvalidFile = errors.New("invalid settlement file")
+
+// Line 结算文件中的一行
+type Line struct {
+	MerchantTransID string
+	Kind            string // payment, refund
+	Amount          float64
+	Currency        string
+	Status          string
+	Date            time.Time
+}
+
+// columnAliases 各字段可接受的列名（统一转为小写、去掉下划线和空格后比较）
+var columnAliases = map[string][]string{
+	"merchantTransID": {"merchanttransid", "merchanttransactionid", "merchantorderid"},
+	"kind":            {"transtype", "transactiontype", "type"},
+	"amount":          {"amount", "transamount", "settlementamount", "value"},
+	"currency":        {"currency", "transcurrency", "settlementcurrency"},
+	"status":          {"status", "transstatus"},
+	"date":            {"transtime", "transactiontime", "settlementdate", "date"},
+}
+
+// ParseSettlementFile 解析Evonet结算/交易CSV，按表头识别列
+func ParseSettlementFile(r io.Reader) ([]Line, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read header: %v", ErrInvalidFile, err)
+	}
+	index := mapColumns(header)
+	for _, required := range []string{"merchantTransID", "amount"} {
+		if _, ok := index[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %s", ErrInvalidFile, required)
+		}
+	}
+
+	var lines []Line
+	for row := 2; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrInvalidFile, row, err)
+		}
+
+		get := func(field string) string {
+			if i, ok := index[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		line := Line{
+			MerchantTransID: get("merchantTransID"),
+			Kind:            normalizeKind(get("kind")),
+			Currency:        strings.ToUpper(get("currency")),
+			Status:          get("status"),
+		}
+		if line.MerchantTransID == "" {
+			continue
+		}
+		if line.Amount, err = strconv.ParseFloat(get("amount"), 64); err != nil {
+			return nil, fmt.Errorf("%w: row %d: invalid amount %q", ErrInvalidFile, row, get("amount"))
+		}
+		if value := get("date"); value != "" {
+			if line.Date, err = parseDate(value); err != nil {
+				return nil, fmt.Errorf("%w: row %d: invalid date %q", ErrInvalidFile, row, value)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func mapColumns(header []string) map[string]int {
+	index := map[string]int{}
+	for i, name := range header {
+		key := strings.ToLower(strings.NewReplacer("_", "", " ", "", "-", "").Replace(strings.TrimSpace(name)))
+		key = strings.TrimPrefix(key, "\ufeff") // Excel导出的UTF-8 BOM
+		for field, aliases := range columnAliases {
+			for _, alias := range aliases {
+				if _, seen := index[field]; !seen && key == alias {
+					index[field] = i
+				}
+			}
+		}
+	}
+	return index
+}
+
+func normalizeKind(value string) string {
+	switch strings.ToLower(value) {
+	case "refund", "refunds", "rfd":
+		return "refund"
+	default:
+		return "payment"
+	}
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unrecognized date format")
+}
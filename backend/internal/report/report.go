@@ -0,0 +1,261 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"payment-demo/internal/fees"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+)
+
+// 导出格式
+const (
+	FormatCSV   = "csv"
+	FormatXLSX  = "xlsx"
+	FormatJSONL = "jsonl"
+)
+
+// 记录类型
+const (
+	RecordPayment       = "payment"
+	RecordRefund        = "refund"
+	RecordMethodTotal   = "method_total"
+	RecordCurrencyTotal = "currency_total"
+)
+
+// Row 报表中的一行：交易明细或汇总
+type Row struct {
+	RecordType      string     `json:"recordType"`
+	Date            *time.Time `json:"date,omitempty"`
+	MerchantTransID string     `json:"merchantTransId,omitempty"`
+	RefundID        string     `json:"refundId,omitempty"`
+	PaymentType     string     `json:"paymentType,omitempty"`
+	PaymentMethod   string     `json:"paymentMethod,omitempty"`
+	Currency        string     `json:"currency"`
+	Status          string     `json:"status,omitempty"`
+	Environment     string     `json:"environment,omitempty"`
+	Amount          float64    `json:"amount"`
+	FeeAmount       float64    `json:"feeAmount,omitempty"` // 手续费（退款行中退还的部分为负）
+	NetAmount       float64    `json:"netAmount,omitempty"` // 扣除手续费后对净收入的影响，退款为负
+
+	// 以下仅用于汇总行
+	PaymentCount int     `json:"paymentCount,omitempty"`
+	RefundCount  int     `json:"refundCount,omitempty"`
+	RefundAmount float64 `json:"refundAmount,omitempty"`
+}
+
+// Report 一段时间内的交易报表
+type Report struct {
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+
+	Transactions []Row // 支付与退款明细
+	ByMethod     []Row // 按币种+支付方式汇总
+	Totals       []Row // 按币种汇总
+}
+
+// settledStatuses 计入交易额的支付状态
+var settledStatuses = map[string]bool{
+	models.StatusCaptured:          true,
+	models.StatusPartiallyRefunded: true,
+	models.StatusRefunded:          true,
+}
+
+// Build 汇总[from, to)内创建的支付和退款
+func Build(from, to time.Time) *Report {
+	inRange := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	r := &Report{From: from, To: to, GeneratedAt: time.Now()}
+	groups := map[[2]string]*Row{}
+	group := func(currency, method string) *Row {
+		key := [2]string{currency, method}
+		if groups[key] == nil {
+			groups[key] = &Row{RecordType: RecordMethodTotal, Currency: currency, PaymentMethod: method}
+		}
+		return groups[key]
+	}
+
+	methods := map[string]string{}
+	payments := map[string]models.PaymentRecord{}
+	for _, p := range store.Payments().List() {
+		methods[p.MerchantTransID] = paymentMethodOf(p)
+		payments[p.MerchantTransID] = p
+		if !inRange(p.CreatedAt) {
+			continue
+		}
+		createdAt := p.CreatedAt
+		row := Row{
+			RecordType:      RecordPayment,
+			Date:            &createdAt,
+			MerchantTransID: p.MerchantTransID,
+			PaymentType:     p.PaymentType,
+			PaymentMethod:   methods[p.MerchantTransID],
+			Currency:        p.Currency,
+			Status:          p.Status,
+			Environment:     p.Environment,
+			Amount:          p.Amount,
+		}
+		if settledStatuses[p.Status] {
+			row.FeeAmount = paymentFee(p)
+			row.NetAmount = p.Amount - row.FeeAmount
+			g := group(p.Currency, methods[p.MerchantTransID])
+			g.PaymentCount++
+			g.Amount += p.Amount
+			g.FeeAmount += row.FeeAmount
+		}
+		r.Transactions = append(r.Transactions, row)
+	}
+
+	for _, refund := range store.Refunds().List() {
+		if !inRange(refund.CreatedAt) {
+			continue
+		}
+		method := methods[refund.MerchantTransID]
+		createdAt := refund.CreatedAt
+		row := Row{
+			RecordType:      RecordRefund,
+			Date:            &createdAt,
+			MerchantTransID: refund.MerchantTransID,
+			RefundID:        refund.RefundID,
+			PaymentMethod:   method,
+			Currency:        refund.Currency,
+			Status:          refund.Status,
+			Environment:     refund.Environment,
+			Amount:          refund.Amount,
+		}
+		if refund.Status == models.RefundSucceeded {
+			row.FeeAmount = refundFee(refund, payments[refund.MerchantTransID])
+			row.NetAmount = -(refund.Amount + row.FeeAmount)
+			g := group(refund.Currency, method)
+			g.RefundCount++
+			g.RefundAmount += refund.Amount
+			g.FeeAmount += row.FeeAmount
+		}
+		r.Transactions = append(r.Transactions, row)
+	}
+
+	sort.SliceStable(r.Transactions, func(i, j int) bool {
+		return r.Transactions[i].Date.Before(*r.Transactions[j].Date)
+	})
+
+	totals := map[string]*Row{}
+	for _, g := range groups {
+		g.NetAmount = g.Amount - g.RefundAmount - g.FeeAmount
+		r.ByMethod = append(r.ByMethod, *g)
+
+		t := totals[g.Currency]
+		if t == nil {
+			t = &Row{RecordType: RecordCurrencyTotal, Currency: g.Currency}
+			totals[g.Currency] = t
+		}
+		t.PaymentCount += g.PaymentCount
+		t.Amount += g.Amount
+		t.RefundCount += g.RefundCount
+		t.RefundAmount += g.RefundAmount
+		t.FeeAmount += g.FeeAmount
+		t.NetAmount += g.NetAmount
+	}
+	for _, t := range totals {
+		r.Totals = append(r.Totals, *t)
+	}
+
+	sort.Slice(r.ByMethod, func(i, j int) bool {
+		if r.ByMethod[i].Currency != r.ByMethod[j].Currency {
+			return r.ByMethod[i].Currency < r.ByMethod[j].Currency
+		}
+		return r.ByMethod[i].PaymentMethod < r.ByMethod[j].PaymentMethod
+	})
+	sort.Slice(r.Totals, func(i, j int) bool {
+		return r.Totals[i].Currency < r.Totals[j].Currency
+	})
+	return r
+}
+
+// Write 按格式输出报表
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return r.writeCSV(w)
+	case FormatXLSX:
+		return r.writeXLSX(w)
+	case FormatJSONL:
+		return r.writeJSONL(w)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// ContentType 返回格式对应的MIME类型
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// IsValidFormat 判断是否为支持的导出格式
+func IsValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatXLSX || format == FormatJSONL
+}
+
+// paymentMethodOf 未指定支付方式时以集成方式区分，Direct API固定为卡支付
+func paymentMethodOf(p models.PaymentRecord) string {
+	switch {
+	case p.PaymentMethod != "":
+		return p.PaymentMethod
+	case p.PaymentType == models.PaymentTypeDirectAPI:
+		return "card"
+	case p.PaymentType != "":
+		return p.PaymentType
+	default:
+		return "unknown"
+	}
+}
+
+// DayRange 返回t所在自然日（本地时区）的[开始, 结束)
+func DayRange(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// paymentFee 支付的手续费；扣款时未记录手续费（如费率表上线前的历史支付）时按当前费率表估算
+func paymentFee(p models.PaymentRecord) float64 {
+	if p.Fees != nil {
+		return p.Fees.Total
+	}
+	estimated, err := fees.Default().ForPayment(p)
+	if err != nil || estimated == nil {
+		return 0
+	}
+	return estimated.Total
+}
+
+// refundFee 退款的手续费，未记录时同样按当前费率表估算
+func refundFee(refund models.Refund, p models.PaymentRecord) float64 {
+	if refund.Fees != nil {
+		return refund.Fees.Total
+	}
+	if p.MerchantTransID == "" {
+		return 0
+	}
+	if p.Fees == nil {
+		if estimated, err := fees.Default().ForPayment(p); err == nil {
+			p.Fees = estimated
+		}
+	}
+	estimated, err := fees.Default().ForRefund(p, refund.Amount)
+	if err != nil || estimated == nil {
+		return 0
+	}
+	return estimated.Total
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// columns CSV与XLSX共用的列
+var columns = []string{
+	"recordType", "date", "merchantTransId", "refundId", "paymentType", "paymentMethod",
+	"currency", "status", "environment", "amount", "feeAmount", "paymentCount", "refundCount", "refundAmount", "netAmount",
+}
+
+// cells 将一行转换为与columns对应的单元格，数值列为float64/int
+func (row Row) cells() []any {
+	date := ""
+	if row.Date != nil {
+		date = row.Date.Format(time.RFC3339)
+	}
+	cells := []any{
+		row.RecordType, date, row.MerchantTransID, row.RefundID, row.PaymentType, row.PaymentMethod,
+		row.Currency, row.Status, row.Environment, row.Amount, row.FeeAmount,
+	}
+	// 明细行不输出计数和退款汇总列；未计入交易额的支付没有净额
+	if row.RecordType == RecordPayment || row.RecordType == RecordRefund {
+		if row.NetAmount == 0 {
+			return append(cells, "", "", "", "")
+		}
+		return append(cells, "", "", "", row.NetAmount)
+	}
+	return append(cells, row.PaymentCount, row.RefundCount, row.RefundAmount, row.NetAmount)
+}
+
+// rows 明细在前，汇总在后
+func (r *Report) rows() []Row {
+	rows := make([]Row, 0, len(r.Transactions)+len(r.ByMethod)+len(r.Totals))
+	rows = append(rows, r.Transactions...)
+	rows = append(rows, r.ByMethod...)
+	return append(rows, r.Totals...)
+}
+
+func (r *Report) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range r.rows() {
+		record := make([]string, 0, len(columns))
+		for _, cell := range row.cells() {
+			record = append(record, formatCell(cell))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *Report) writeJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, row := range r.rows() {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatCell(cell any) string {
+	switch v := cell.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
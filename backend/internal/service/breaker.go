@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+)
+
+// ErrCircuitOpen Evonet连续失败后熔断，请求未发送，可稍后重试
+var ErrCircuitOpen = errors.New("evonet circuit breaker open")
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open" // 冷却结束，放行一个试探请求
+)
+
+// BreakerState 熔断器状态快照，用于就绪检查
+type BreakerState struct {
+	Environment string     `json:"environment"`
+	State       string     `json:"state"`
+	Failures    int        `json:"failures"`
+	OpenedAt    *time.Time `json:"openedAt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+// circuitBreaker 连续threshold次网络错误或5xx后熔断，cooldown后放行一个试探请求，成功则恢复
+type circuitBreaker struct {
+	env       string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool // 半开状态下已有试探请求在途
+	lastErr  string
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = map[config.APIEnvironment]*circuitBreaker{}
+)
+
+// evonetBreaker 返回指定Evonet环境的熔断器，各环境独立计数
+func evonetBreaker(env config.APIEnvironment) *circuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[env]
+	if !ok {
+		cfg := config.Load()
+		b = &circuitBreaker{
+			env:       string(env),
+			threshold: cfg.EvonetBreakerThreshold,
+			cooldown:  cfg.EvonetBreakerCooldown,
+			state:     BreakerClosed,
+		}
+		breakers[env] = b
+	}
+	return b
+}
+
+// EvonetBreakers 返回已使用过的各环境熔断器状态
+func EvonetBreakers() []BreakerState {
+	breakersMu.Lock()
+	list := make([]*circuitBreaker, 0, len(breakers))
+	for _, b := range breakers {
+		list = append(list, b)
+	}
+	breakersMu.Unlock()
+
+	states := make([]BreakerState, 0, len(list))
+	for _, b := range list {
+		states = append(states, b.snapshot())
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Environment < states[j].Environment })
+	return states
+}
+
+// CheckEvonetBreaker 当前环境的熔断器打开时返回错误
+func CheckEvonetBreaker() error {
+	state := evonetBreaker(config.Load().GetCurrentAPIEnv()).snapshot()
+	if state.State == BreakerOpen {
+		return fmt.Errorf("circuit open for %s after %d failures: %s", state.Environment, state.Failures, state.LastError)
+	}
+	return nil
+}
+
+// allow 判断是否可以发送请求；threshold<=0时不熔断
+func (b *circuitBreaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		fmt.Printf("[Breaker] %s 冷却结束，放行试探请求\n", b.env)
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record 记录请求结果，err为nil表示Evonet正常响应
+func (b *circuitBreaker) record(err error) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		if b.state != BreakerClosed {
+			fmt.Printf("[Breaker] %s 已恢复\n", b.env)
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		b.lastErr = ""
+		return
+	}
+
+	b.failures++
+	b.lastErr = err.Error()
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		if b.state != BreakerOpen {
+			fmt.Printf("[Breaker] %s 连续失败%d次，熔断%s: %v\n", b.env, b.failures, b.cooldown, err)
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) snapshot() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state := BreakerState{Environment: b.env, State: b.state, Failures: b.failures, LastError: b.lastErr}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		state.OpenedAt = &openedAt
+	}
+	return state
+}
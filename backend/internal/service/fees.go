@@ -0,0 +1,22 @@
+package service
+
+import (
+	"fmt"
+
+	"payment-demo/internal/fees"
+	"payment-demo/internal/models"
+)
+
+// applyCaptureFees 首次扣款成功时按费率表计算手续费和净额，计算失败不影响状态更新
+func applyCaptureFees(p *models.PaymentRecord) {
+	if p.Fees != nil {
+		return
+	}
+	f, err := fees.Default().ForPayment(*p)
+	if err != nil {
+		fmt.Printf("[PaymentService] 计算手续费失败 - merchantTransID: %s, error: %v\n", p.MerchantTransID, err)
+		return
+	}
+	p.Fees = f
+	p.NetAmount = fees.NetAmount(*p)
+}
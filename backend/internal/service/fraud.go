@@ -0,0 +1,50 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"payment-demo/internal/fraud"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+)
+
+// checkFraud 在发送到Evonet前执行风控规则，决策记录到支付记录上
+// block时支付直接置为failed并返回fraud.ErrBlocked；review只记录决策，支付照常发送
+// 规则执行异常时放行，避免影响正常支付
+func (s *PaymentService) checkFraud(req *models.PaymentRequest) error {
+	if !s.config.FraudChecksEnabled {
+		return nil
+	}
+
+	engine := fraud.Default()
+	assessment, err := engine.Evaluate(engine.NewInput(req))
+	if err != nil {
+		fmt.Printf("[Fraud] 风控评估失败，已放行 - merchantTransID: %s, error: %v\n", req.MerchantTransID, err)
+		return nil
+	}
+
+	if _, err := store.Payments().Update(req.MerchantTransID, func(p *models.PaymentRecord) error {
+		p.RiskDecision = assessment.Decision
+		return nil
+	}); err != nil {
+		fmt.Printf("[Fraud] 保存风控决策失败 - merchantTransID: %s, error: %v\n", req.MerchantTransID, err)
+	}
+
+	if assessment.Decision == models.RiskAllow {
+		return nil
+	}
+	reasons := make([]string, 0, len(assessment.Rules))
+	for _, r := range assessment.Rules {
+		reasons = append(reasons, r.Rule)
+	}
+	fmt.Printf("[Fraud] %s - merchantTransID: %s, rules: %s\n", assessment.Decision, req.MerchantTransID, strings.Join(reasons, ","))
+
+	if assessment.Decision == models.RiskBlock {
+		if _, err := transitionPayment(req.MerchantTransID, models.StatusFailed, "fraud"); err != nil {
+			fmt.Printf("[Fraud] 更新支付状态失败 - merchantTransID: %s, error: %v\n", req.MerchantTransID, err)
+		}
+		return fraud.ErrBlocked
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"payment-demo/internal/fx"
+	"payment-demo/internal/models"
+)
+
+// baseAmount 计算支付对应的基础币种金额（返回基础币种到支付币种的换算）：使用锁定报价时取报价中的金额和汇率，
+// 否则按当前汇率换算（仅用于记录，不影响支付金额）；没有汇率时不记录
+func (s *PaymentService) baseAmount(req *models.PaymentRequest) (fx.Conversion, error) {
+	if req.FXQuoteID != "" {
+		quote, err := fx.CheckQuote(req.FXQuoteID, req.Currency, req.Amount)
+		if err != nil {
+			return fx.Conversion{}, err
+		}
+		return fx.Conversion{From: quote.BaseCurrency, To: quote.Currency, Amount: quote.BaseAmount, Converted: quote.Amount, Rate: quote.Rate}, nil
+	}
+
+	base := s.config.FXBaseCurrency
+	if strings.EqualFold(req.Currency, base) {
+		return fx.Conversion{From: base, To: base, Amount: req.Amount, Converted: req.Amount}, nil
+	}
+	conv, err := fx.Convert(req.Amount, req.Currency, base)
+	if err == nil {
+		// 与报价一致记录为 1基础币种 = rate支付币种
+		conv.Rate, err = fx.Rate(base, req.Currency)
+	}
+	if err != nil {
+		fmt.Printf("[PaymentService] 无法换算基础币种金额 - currency: %s, error: %v\n", req.Currency, err)
+		return fx.Conversion{}, nil
+	}
+	return fx.Conversion{From: base, To: conv.From, Amount: conv.Converted, Converted: req.Amount, Rate: conv.Rate}, nil
+}
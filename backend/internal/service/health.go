@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+)
+
+// evonetProbe 缓存的Evonet连通性探测结果，切换环境后重新探测
+var evonetProbe struct {
+	mu        sync.Mutex
+	env       config.APIEnvironment
+	checkedAt time.Time
+	err       error
+}
+
+// ProbeEvonet 探测当前环境的Evonet API是否可达，结果在HealthProbeTTL内复用
+// 只要收到5xx以外的HTTP响应即视为可达
+func ProbeEvonet(ctx context.Context) error {
+	cfg := config.Load()
+	env := cfg.GetCurrentAPIEnv()
+
+	evonetProbe.mu.Lock()
+	defer evonetProbe.mu.Unlock()
+	if evonetProbe.env == env && !evonetProbe.checkedAt.IsZero() && time.Since(evonetProbe.checkedAt) < cfg.HealthProbeTTL {
+		return evonetProbe.err
+	}
+
+	evonetProbe.env = env
+	evonetProbe.err = probeURL(ctx, cfg.GetCurrentEvonetConfig().APIURL, cfg.HealthProbeTimeout)
+	evonetProbe.checkedAt = time.Now()
+	return evonetProbe.err
+}
+
+func probeURL(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s unreachable: %w", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+	}
+	return nil
+}
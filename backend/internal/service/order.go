@@ -0,0 +1,76 @@
+package service
+
+import (
+	"payment-demo/internal/models"
+	"payment-demo/internal/utils"
+)
+
+// addOrderInfo 将订单明细、买家和收货地址写入Evonet的merchantOrderInfo，金额格式与transAmount一致
+func addOrderInfo(info map[string]interface{}, order *models.Order, currency string) {
+	amount := func(v float64) map[string]interface{} {
+		return map[string]interface{}{"currency": currency, "value": utils.FormatAmount(v)}
+	}
+
+	items := make([]map[string]interface{}, 0, len(order.Items))
+	for _, item := range order.Items {
+		line := map[string]interface{}{
+			"name":       item.Name,
+			"quantity":   item.Quantity,
+			"unitPrice":  amount(item.UnitPrice),
+			"lineAmount": amount(item.Subtotal()),
+		}
+		if item.SKU != "" {
+			line["sku"] = item.SKU
+		}
+		if item.Category != "" {
+			line["category"] = item.Category
+		}
+		if item.Discount > 0 {
+			line["discountAmount"] = amount(item.Discount)
+		}
+		if item.Tax > 0 {
+			line["taxAmount"] = amount(item.Tax)
+		}
+		items = append(items, line)
+	}
+	info["goodsInfo"] = items
+
+	if order.Discount > 0 {
+		info["discountAmount"] = amount(order.Discount)
+	}
+	if order.Tax > 0 {
+		info["taxAmount"] = amount(order.Tax)
+	}
+	if order.Shipping > 0 {
+		info["shippingAmount"] = amount(order.Shipping)
+	}
+	if b := order.Buyer; b != nil {
+		info["buyerInfo"] = withoutEmpty(map[string]interface{}{
+			"reference": b.ID,
+			"name":      b.Name,
+			"email":     b.Email,
+			"phone":     b.Phone,
+		})
+	}
+	if a := order.ShippingAddress; a != nil {
+		info["shippingInfo"] = withoutEmpty(map[string]interface{}{
+			"name":       a.Name,
+			"street":     a.Line1,
+			"street2":    a.Line2,
+			"city":       a.City,
+			"state":      a.State,
+			"postalCode": a.PostalCode,
+			"country":    a.Country,
+		})
+	}
+}
+
+// withoutEmpty 去掉未填写的字段
+func withoutEmpty(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if v == "" {
+			delete(m, k)
+		}
+	}
+	return m
+}
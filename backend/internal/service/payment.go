@@ -11,14 +11,23 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"payment-demo/config"
+	"payment-demo/internal/fx"
 	"payment-demo/internal/models"
+	"payment-demo/internal/store"
 	"payment-demo/internal/utils"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// ErrDuplicateMerchantTransID merchantTransId已被使用
+var ErrDuplicateMerchantTransID = errors.New("merchantTransId already exists")
+
+// merchantTransIDPlaceholder returnUrl中的占位符，会被替换为服务端生成的ID
+const merchantTransIDPlaceholder = "{merchantTransId}"
+
 type PaymentService struct {
 	config *config.Config
 	client *http.Client
@@ -55,7 +64,18 @@ func NewPaymentService() *PaymentService {
 
 // 创建支付交互（LinkPay和Drop-in）
 func (s *PaymentService) CreateInteraction(req *models.PaymentRequest) (*models.PaymentResponse, error) {
+	paymentType := req.PaymentType
+	if paymentType != models.PaymentTypeDropIn {
+		paymentType = models.PaymentTypeLinkPay
+	}
+	if err := s.reservePaymentRecord(req, paymentType); err != nil {
+		return nil, err
+	}
+	return s.sendInteraction(req)
+}
 
+// sendInteraction 为已占位的支付记录向Evonet创建交互
+func (s *PaymentService) sendInteraction(req *models.PaymentRequest) (*models.PaymentResponse, error) {
 	// 构建Evonet API请求
 	// 根据Evonet API文档的标准格式
 	evonetReq := map[string]interface{}{
@@ -74,6 +94,9 @@ func (s *PaymentService) CreateInteraction(req *models.PaymentRequest) (*models.
 	if req.PaymentMethod != "" {
 		evonetReq["merchantOrderInfo"].(map[string]interface{})["enabledPaymentMethod"] = []string{req.PaymentMethod}
 	}
+	if req.Order != nil {
+		addOrderInfo(evonetReq["merchantOrderInfo"].(map[string]interface{}), req.Order, req.Currency)
+	}
 
 	// 发送请求到Evonet
 	resp, err := s.sendEvonetRequest("POST", "/interaction", evonetReq)
@@ -81,6 +104,7 @@ func (s *PaymentService) CreateInteraction(req *models.PaymentRequest) (*models.
 		// 添加详细的错误日志
 		fmt.Printf("Interaction API Error: %v\n", err)
 		fmt.Printf("Request data: %+v\n", evonetReq)
+		s.releasePaymentRecord(req.MerchantTransID)
 		return nil, fmt.Errorf("failed to send request to Evonet: %w", err)
 	}
 
@@ -89,6 +113,7 @@ func (s *PaymentService) CreateInteraction(req *models.PaymentRequest) (*models.
 		// 添加详细的响应日志
 		fmt.Printf("Failed to parse Evonet response: %v\n", err)
 		fmt.Printf("Raw response: %s\n", string(resp))
+		s.releasePaymentRecord(req.MerchantTransID)
 		return nil, fmt.Errorf("failed to parse Evonet response: %w", err)
 	}
 
@@ -105,6 +130,8 @@ func (s *PaymentService) CreateInteraction(req *models.PaymentRequest) (*models.
 		Message:         evonetResp.Result.Message,
 	}
 
+	s.completePaymentRecord(req.MerchantTransID, response)
+
 	return response, nil
 }
 
@@ -113,6 +140,12 @@ func (s *PaymentService) CreateDirectPayment(req *models.PaymentRequest) (*model
 	if req.CardInfo == nil {
 		return nil, fmt.Errorf("card information is required for direct payment")
 	}
+	if err := s.reservePaymentRecord(req, models.PaymentTypeDirectAPI); err != nil {
+		return nil, err
+	}
+	if err := s.checkFraud(req); err != nil {
+		return nil, err
+	}
 
 	// 构建Evonet Direct API请求
 	evonetReq := map[string]interface{}{
@@ -139,15 +172,22 @@ func (s *PaymentService) CreateDirectPayment(req *models.PaymentRequest) (*model
 		"returnURL":           req.ReturnURL,
 		"webhook":             req.WebhookURL,
 	}
+	if req.Order != nil {
+		orderInfo := map[string]interface{}{"merchantOrderID": req.MerchantTransID}
+		addOrderInfo(orderInfo, req.Order, req.Currency)
+		evonetReq["merchantOrderInfo"] = orderInfo
+	}
 
 	// 发送请求到Evonet
 	resp, err := s.sendEvonetRequest("POST", "/payment", evonetReq)
 	if err != nil {
+		s.releasePaymentRecord(req.MerchantTransID)
 		return nil, fmt.Errorf("failed to send request to Evonet: %w", err)
 	}
 
 	var evonetResp models.EvonetPaymentResponse
 	if err := json.Unmarshal(resp, &evonetResp); err != nil {
+		s.releasePaymentRecord(req.MerchantTransID)
 		return nil, fmt.Errorf("failed to parse Evonet response: %w", err)
 	}
 
@@ -174,26 +214,144 @@ func (s *PaymentService) CreateDirectPayment(req *models.PaymentRequest) (*model
 		}
 	}
 
+	if response.MerchantTransID == "" {
+		response.MerchantTransID = req.MerchantTransID
+	}
+	s.completePaymentRecord(req.MerchantTransID, response)
+
 	return response, nil
 }
 
+// reservePaymentRecord 调用Evonet前先写入本地记录占用merchantTransId，由存储保证唯一
+// 请求未提供ID或配置为服务端生成时使用ULID生成，冲突时重新生成
+func (s *PaymentService) reservePaymentRecord(req *models.PaymentRequest, paymentType string) error {
+	generate := req.MerchantTransID == "" || s.config.GenerateMerchantTransID
+	base, err := s.baseAmount(req)
+	if err != nil {
+		return err
+	}
+
+	// returnUrl中可使用占位符回传服务端生成的ID，每次生成都从原始模板替换，冲突重试时不会残留旧ID
+	returnURLTemplate := req.ReturnURL
+	for attempt := 1; ; attempt++ {
+		if generate {
+			req.MerchantTransID = utils.GenerateMerchantTransID(s.config.MerchantTransIDPrefix)
+			req.ReturnURL = strings.ReplaceAll(returnURLTemplate, merchantTransIDPlaceholder, req.MerchantTransID)
+		}
+
+		now := time.Now()
+		record := models.PaymentRecord{
+			MerchantTransID: req.MerchantTransID,
+			PaymentType:     paymentType,
+			PaymentMethod:   req.PaymentMethod,
+			Status:          models.StatusPending,
+			Amount:          req.Amount,
+			Currency:        req.Currency,
+			Environment:     string(s.config.GetCurrentAPIEnv()),
+			PaymentLinkID:   req.PaymentLinkID,
+			Order:           req.Order,
+			Metadata:        req.Metadata,
+			BaseAmount:      base.Amount,
+			BaseCurrency:    base.From,
+			FXRate:          base.Rate,
+			FXQuoteID:       req.FXQuoteID,
+			BillingCountry:  strings.ToUpper(req.BillingCountry),
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+
+		err := store.Payments().Insert(record.MerchantTransID, record)
+		if errors.Is(err, store.ErrDuplicate) {
+			if generate && attempt < 3 {
+				continue
+			}
+			return fmt.Errorf("%w: %s", ErrDuplicateMerchantTransID, req.MerchantTransID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save payment record: %w", err)
+		}
+		if req.FXQuoteID != "" {
+			if _, err := fx.Redeem(req.FXQuoteID, record.MerchantTransID, req.Currency, req.Amount); err != nil {
+				s.releasePaymentRecord(record.MerchantTransID)
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// releasePaymentRecord 请求未送达Evonet时删除占位记录，允许使用同一ID重试
+func (s *PaymentService) releasePaymentRecord(merchantTransID string) {
+	if record, ok := store.Payments().Get(merchantTransID); ok && record.FXQuoteID != "" {
+		fx.Release(record.FXQuoteID, merchantTransID)
+	}
+	if err := store.Payments().Delete(merchantTransID); err != nil {
+		fmt.Printf("[PaymentService] 删除占位记录失败 - merchantTransID: %s, error: %v\n", merchantTransID, err)
+	}
+}
+
+// completePaymentRecord 根据Evonet响应补全本地记录并更新状态
+func (s *PaymentService) completePaymentRecord(merchantTransID string, resp *models.PaymentResponse) {
+	_, err := store.Payments().Update(merchantTransID, func(p *models.PaymentRecord) error {
+		p.SessionID = resp.SessionID
+		p.LinkURL = resp.LinkURL
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("[PaymentService] 更新支付记录失败 - merchantTransID: %s, error: %v\n", merchantTransID, err)
+		return
+	}
+
+	status := normalizeStatus(resp.Status)
+	if !resp.Success {
+		status = models.StatusFailed
+	}
+	s.applyQueriedStatus(merchantTransID, status, "create")
+}
+
 // GetPaymentStatus 获取支付状态
 func (s *PaymentService) GetPaymentStatus(merchantTransID string) (*models.Payment, error) {
 	// 调用Evonet API查询状态
-	return s.queryRealPaymentStatus(merchantTransID)
+	payment, err := s.queryRealPaymentStatus(merchantTransID)
+	if err != nil {
+		return nil, err
+	}
+	s.applyQueriedStatus(merchantTransID, payment.Status, "query")
+	withLocalMetadata(merchantTransID, payment)
+	return payment, nil
 }
 
 // GetInteractionStatus 获取交互状态（用于LinkPay和Drop-in）
 func (s *PaymentService) GetInteractionStatus(merchantOrderID string) (*models.Payment, error) {
 	// 调用Evonet API查询交互状态
-	return s.queryRealInteractionStatus(merchantOrderID)
+	payment, err := s.queryRealInteractionStatus(merchantOrderID)
+	if err != nil {
+		return nil, err
+	}
+	s.applyQueriedStatus(merchantOrderID, payment.Status, "query")
+	withLocalMetadata(merchantOrderID, payment)
+	return payment, nil
+}
+
+// withLocalMetadata Evonet不返回商户metadata，从本地支付记录补充
+func withLocalMetadata(merchantTransID string, payment *models.Payment) {
+	if record, ok := store.Payments().Get(merchantTransID); ok {
+		payment.Metadata = record.Metadata
+	}
+}
+
+// applyQueriedStatus 将查询到的状态通过状态机同步到本地记录
+func (s *PaymentService) applyQueriedStatus(merchantTransID, status, source string) {
+	if _, err := transitionPayment(merchantTransID, status, source); err != nil {
+		fmt.Printf("[PaymentService] 同步本地状态失败 - merchantTransID: %s, error: %v\n", merchantTransID, err)
+	}
 }
 
 // queryRealPaymentStatus 查询真实支付状态（Direct API）
 func (s *PaymentService) queryRealPaymentStatus(merchantTransID string) (*models.Payment, error) {
 	// 发送查询请求到Evonet
 	fmt.Printf("[PaymentService] 查询Direct API支付状态 - merchantTransID: %s\n", merchantTransID)
-	resp, err := s.sendEvonetRequest("GET", fmt.Sprintf("/payment/%s", merchantTransID), nil)
+	resp, err := s.sendEvonetRequest("GET", "/payment/"+url.PathEscape(merchantTransID), nil)
 	if err != nil {
 		fmt.Printf("[PaymentService] Direct API查询失败: %v\n", err)
 		return nil, fmt.Errorf("failed to get payment status: %w", err)
@@ -244,7 +402,7 @@ func (s *PaymentService) queryRealPaymentStatus(merchantTransID string) (*models
 	// 转换为标准Payment结构
 	return &models.Payment{
 		MerchantTransID: apiResponse.Payment.MerchantTransInfo.MerchantTransID,
-		Status:          s.normalizeStatus(apiResponse.Payment.Status),
+		Status:          normalizeStatus(apiResponse.Payment.Status),
 		Amount:          apiResponse.Payment.Amount,
 		Currency:        apiResponse.Payment.Currency,
 		CreatedAt:       time.Now(), // API可能不返回创建时间，使用当前时间
@@ -255,7 +413,7 @@ func (s *PaymentService) queryRealPaymentStatus(merchantTransID string) (*models
 func (s *PaymentService) queryRealInteractionStatus(merchantOrderID string) (*models.Payment, error) {
 	// 发送查询请求到Evonet
 	fmt.Printf("[PaymentService] 查询Interaction状态 - merchantOrderID: %s\n", merchantOrderID)
-	resp, err := s.sendEvonetRequest("GET", fmt.Sprintf("/interaction/%s", merchantOrderID), nil)
+	resp, err := s.sendEvonetRequest("GET", "/interaction/"+url.PathEscape(merchantOrderID), nil)
 	if err != nil {
 		fmt.Printf("[PaymentService] Interaction查询失败: %v\n", err)
 		return nil, fmt.Errorf("failed to get interaction status: %w", err)
@@ -324,7 +482,7 @@ func (s *PaymentService) queryRealInteractionStatus(merchantOrderID string) (*mo
 
 	return &models.Payment{
 		MerchantTransID: apiResponse.MerchantOrderInfo.MerchantOrderID,
-		Status:          s.normalizeStatus(status),
+		Status:          normalizeStatus(status),
 		Amount:          amount,
 		Currency:        apiResponse.TransactionInfo.TransAmount.Currency,
 		CreatedAt:       time.Now(), // API可能不返回创建时间，使用当前时间
@@ -332,7 +490,7 @@ func (s *PaymentService) queryRealInteractionStatus(merchantOrderID string) (*mo
 }
 
 // normalizeStatus 标准化状态名称
-func (s *PaymentService) normalizeStatus(status string) string {
+func normalizeStatus(status string) string {
 	// 将不同的状态名称标准化为一致的格式
 	switch strings.ToLower(status) {
 	case "success", "completed", "paid", "captured":
@@ -390,9 +548,17 @@ func (s *PaymentService) sendEvonetRequest(method, endpoint string, data interfa
 	req.Header.Set("Idempotency-Key", idempotencyKey)
 	fmt.Printf("[sendEvonetRequest] Idempotency-Key: %s\n", idempotencyKey)
 
+	// 熔断期间不发送请求，调用方可按“未送达”处理
+	breaker := evonetBreaker(s.config.GetCurrentAPIEnv())
+	if err := breaker.allow(); err != nil {
+		fmt.Printf("[sendEvonetRequest] 熔断中，请求未发送: %s %s\n", method, url)
+		return nil, err
+	}
+
 	// 发送请求
 	resp, err := s.client.Do(req)
 	if err != nil {
+		breaker.record(err)
 		fmt.Printf("[sendEvonetRequest] Request failed: %v\n", err)
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -402,9 +568,16 @@ func (s *PaymentService) sendEvonetRequest(method, endpoint string, data interfa
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
+		breaker.record(err)
 		fmt.Printf("[sendEvonetRequest] Failed to read response: %v\n", err)
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	// 只有网络错误和5xx计入熔断，4xx说明Evonet正常工作
+	if resp.StatusCode >= 500 {
+		breaker.record(fmt.Errorf("status %d", resp.StatusCode))
+	} else {
+		breaker.record(nil)
+	}
 
 	fmt.Printf("[sendEvonetRequest] Response Body: %s\n", string(responseBody))
 
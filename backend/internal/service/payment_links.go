@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+var (
+	// ErrPaymentLinkUnavailable 支付链接已停用、已过期或使用次数已满
+	ErrPaymentLinkUnavailable = errors.New("payment link is not available")
+	// ErrInvalidPaymentLinkAmount 金额与链接的固定金额或金额范围不符
+	ErrInvalidPaymentLinkAmount = errors.New("invalid amount for payment link")
+)
+
+// paymentLinkMu 检查使用次数和占位支付记录需要原子执行，避免单次链接被并发重复使用
+var paymentLinkMu sync.Mutex
+
+// PaymentLinks 支付链接集合
+func PaymentLinks() *store.Collection[models.PaymentLink] {
+	return store.Open[models.PaymentLink]("payment_links")
+}
+
+// CreatePaymentLink 创建支付链接，未指定过期时间时使用默认有效期
+func (s *PaymentService) CreatePaymentLink(req *models.PaymentLinkRequest) (models.PaymentLink, error) {
+	now := time.Now()
+	expiresAt := req.ExpiresAt
+	if expiresAt == nil && s.config.PaymentLinkDefaultExpiry > 0 {
+		t := now.Add(s.config.PaymentLinkDefaultExpiry)
+		expiresAt = &t
+	}
+
+	link := models.PaymentLink{
+		ID:            utils.GenerateID("plink"),
+		Active:        true,
+		Amount:        req.Amount,
+		MinAmount:     req.MinAmount,
+		MaxAmount:     req.MaxAmount,
+		Currency:      req.Currency,
+		Description:   req.Description,
+		PaymentMethod: req.PaymentMethod,
+		ReturnURL:     req.ReturnURL,
+		MaxUses:       req.MaxUses,
+		ExpiresAt:     expiresAt,
+		Metadata:      req.Metadata,
+		Environment:   string(s.config.GetCurrentAPIEnv()),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	link.URL = s.config.PublicBaseURL + "/pay/" + link.ID
+	if err := PaymentLinks().Insert(link.ID, link); err != nil {
+		return models.PaymentLink{}, fmt.Errorf("failed to save payment link: %w", err)
+	}
+	return WithLinkUsage(link), nil
+}
+
+// DeactivatePaymentLink 停用支付链接，已发起的支付不受影响
+func (s *PaymentService) DeactivatePaymentLink(id string) (models.PaymentLink, error) {
+	link, err := PaymentLinks().Update(id, func(l *models.PaymentLink) error {
+		if !l.Active {
+			return nil
+		}
+		now := time.Now()
+		l.Active = false
+		l.DeactivatedAt = &now
+		l.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return link, err
+	}
+	return WithLinkUsage(link), nil
+}
+
+// PaymentLinkRequest 根据链接和顾客输入的金额构建LinkPay支付请求，尚未校验链接是否可用
+func PaymentLinkRequest(link models.PaymentLink, amount float64) (*models.PaymentRequest, error) {
+	if link.Amount > 0 {
+		if amount > 0 && amount != link.Amount {
+			return nil, fmt.Errorf("%w: link has a fixed amount of %g", ErrInvalidPaymentLinkAmount, link.Amount)
+		}
+		amount = link.Amount
+	}
+	switch {
+	case amount <= 0:
+		return nil, fmt.Errorf("%w: amount is required", ErrInvalidPaymentLinkAmount)
+	case link.MinAmount > 0 && amount < link.MinAmount:
+		return nil, fmt.Errorf("%w: amount must be at least %g", ErrInvalidPaymentLinkAmount, link.MinAmount)
+	case link.MaxAmount > 0 && amount > link.MaxAmount:
+		return nil, fmt.Errorf("%w: amount must be at most %g", ErrInvalidPaymentLinkAmount, link.MaxAmount)
+	}
+
+	return &models.PaymentRequest{
+		Amount:        amount,
+		Currency:      link.Currency,
+		PaymentType:   models.PaymentTypeLinkPay,
+		PaymentMethod: link.PaymentMethod,
+		ReturnURL:     link.ReturnURL,
+		PaymentLinkID: link.ID,
+		Metadata:      link.Metadata,
+	}, nil
+}
+
+// CreatePaymentLinkInteraction 确认链接可用后占位支付记录并向Evonet创建LinkPay交互
+func (s *PaymentService) CreatePaymentLinkInteraction(req *models.PaymentRequest) (*models.PaymentResponse, error) {
+	paymentLinkMu.Lock()
+	link, ok := PaymentLinks().Get(req.PaymentLinkID)
+	if !ok {
+		paymentLinkMu.Unlock()
+		return nil, store.ErrNotFound
+	}
+	if link = WithLinkUsage(link); link.Status != models.PaymentLinkActive {
+		paymentLinkMu.Unlock()
+		return nil, fmt.Errorf("%w: link is %s", ErrPaymentLinkUnavailable, link.Status)
+	}
+	if link.MaxUses > 0 && countOpenLinkPayments(link.ID) >= link.MaxUses {
+		paymentLinkMu.Unlock()
+		return nil, fmt.Errorf("%w: a payment through this link is already in progress", ErrPaymentLinkUnavailable)
+	}
+	err := s.reservePaymentRecord(req, models.PaymentTypeLinkPay)
+	paymentLinkMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sendInteraction(req)
+}
+
+// LinkPayments 通过支付链接发起的支付，按创建时间排序
+func LinkPayments(linkID string) []models.PaymentRecord {
+	payments := store.Payments().Filter(func(p models.PaymentRecord) bool {
+		return p.PaymentLinkID == linkID
+	})
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].CreatedAt.Before(payments[j].CreatedAt)
+	})
+	return payments
+}
+
+// WithLinkUsage 填充已扣款次数并计算当前状态
+func WithLinkUsage(link models.PaymentLink) models.PaymentLink {
+	link.Uses = 0
+	for _, p := range store.Payments().Filter(func(p models.PaymentRecord) bool { return p.PaymentLinkID == link.ID }) {
+		if isPaidStatus(p.Status) {
+			link.Uses++
+		}
+	}
+	link.Status = linkStatus(link, time.Now())
+	return link
+}
+
+func linkStatus(link models.PaymentLink, now time.Time) string {
+	switch {
+	case !link.Active:
+		return models.PaymentLinkInactive
+	case link.MaxUses > 0 && link.Uses >= link.MaxUses:
+		return models.PaymentLinkCompleted
+	case link.ExpiresAt != nil && !now.Before(*link.ExpiresAt):
+		return models.PaymentLinkExpired
+	default:
+		return models.PaymentLinkActive
+	}
+}
+
+// countOpenLinkPayments 已扣款或仍在进行中的支付数；失败、取消、过期的尝试不占用次数
+func countOpenLinkPayments(linkID string) int {
+	return len(store.Payments().Filter(func(p models.PaymentRecord) bool {
+		if p.PaymentLinkID != linkID {
+			return false
+		}
+		switch p.Status {
+		case models.StatusFailed, models.StatusCancelled, models.StatusExpired:
+			return false
+		}
+		return true
+	}))
+}
+
+// isPaidStatus 已扣款（含之后发生退款）的状态
+func isPaidStatus(status string) bool {
+	return status == models.StatusCaptured || status == models.StatusPartiallyRefunded || status == models.StatusRefunded
+}
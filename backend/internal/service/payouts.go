@@ -0,0 +1,216 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/health"
+	"payment-demo/internal/models"
+	"payment-demo/internal/payout"
+	"payment-demo/internal/utils"
+)
+
+const payoutPollerWorker = "payout_poller"
+
+// payoutReturnWindow 出款成功后仍需关注退回的时长
+const payoutReturnWindow = 72 * time.Hour
+
+// PayoutProvider 按PAYOUT_PROVIDER返回出款通道
+func PayoutProvider() payout.Provider {
+	cfg := config.Load()
+	if cfg.PayoutProvider == "evonet" {
+		return &evonetPayoutProvider{service: NewPaymentService()}
+	}
+	return payout.NewSimulator(cfg.PayoutSimulatorDelay)
+}
+
+// evonetPayoutProvider 通过Evonet付款接口出款
+type evonetPayoutProvider struct {
+	service *PaymentService
+}
+
+// evonetPayoutResponse Evonet付款接口的响应
+type evonetPayoutResponse struct {
+	Result struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"result"`
+	Payout struct {
+		Status        string `json:"status"`
+		EvoTransID    string `json:"evoTransID"`
+		FailureReason string `json:"failureReason"`
+	} `json:"payout"`
+}
+
+func (e *evonetPayoutProvider) Name() string {
+	return "evonet"
+}
+
+// Submit 以付款ID作为merchantTransID提交付款
+func (e *evonetPayoutProvider) Submit(p models.Payout, b models.Beneficiary) (payout.Result, error) {
+	req := map[string]interface{}{
+		"merchantTransInfo": map[string]interface{}{
+			"merchantTransID":   p.ID,
+			"merchantTransTime": p.CreatedAt.Format("2006-01-02T15:04:05+08:00"),
+		},
+		"transAmount": map[string]interface{}{
+			"currency": p.Currency,
+			"value":    utils.FormatAmount(p.Amount),
+		},
+		"beneficiary": withoutEmpty(map[string]interface{}{
+			"name":    b.Name,
+			"type":    b.Type,
+			"email":   b.Email,
+			"country": b.Country,
+			"bankAccount": withoutEmpty(map[string]interface{}{
+				"accountName":   b.BankAccount.AccountName,
+				"accountNumber": b.BankAccount.AccountNumber,
+				"bankName":      b.BankAccount.BankName,
+				"bankCode":      b.BankAccount.BankCode,
+				"swiftCode":     b.BankAccount.SwiftCode,
+			}),
+		}),
+	}
+	if p.Description != "" {
+		req["remark"] = p.Description
+	}
+
+	resp, err := e.service.sendEvonetRequest("POST", "/payout", req)
+	if err != nil {
+		return payout.Result{}, fmt.Errorf("failed to send request to Evonet: %w", err)
+	}
+	return parsePayoutResponse(resp)
+}
+
+// Query 查询付款状态
+func (e *evonetPayoutProvider) Query(p models.Payout) (payout.Result, error) {
+	resp, err := e.service.sendEvonetRequest("GET", "/payout/"+url.PathEscape(p.ID), nil)
+	if err != nil {
+		return payout.Result{}, fmt.Errorf("failed to query Evonet: %w", err)
+	}
+	return parsePayoutResponse(resp)
+}
+
+// parsePayoutResponse 结果码非S开头视为被拒绝
+func parsePayoutResponse(resp []byte) (payout.Result, error) {
+	var evonetResp evonetPayoutResponse
+	if err := json.Unmarshal(resp, &evonetResp); err != nil {
+		return payout.Result{}, fmt.Errorf("failed to parse Evonet response: %w", err)
+	}
+	code := evonetResp.Result.Code
+	if code == "" || code[0] != 'S' {
+		return payout.Result{
+			Status:        models.PayoutFailed,
+			FailureReason: strings.TrimSpace(code + " " + evonetResp.Result.Message),
+		}, nil
+	}
+	return payout.Result{
+		Status:        payoutStatus(evonetResp.Payout.Status),
+		Reference:     evonetResp.Payout.EvoTransID,
+		FailureReason: evonetResp.Payout.FailureReason,
+	}, nil
+}
+
+// payoutStatus 将Evonet付款状态映射为本地状态，无法识别时返回空（保持原状态）
+func payoutStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "pending", "processing", "submitted", "in_progress":
+		return models.PayoutProcessing
+	case "success", "succeeded", "completed", "paid":
+		return models.PayoutPaid
+	case "failed", "rejected", "declined", "cancelled":
+		return models.PayoutFailed
+	case "returned", "reversed":
+		return models.PayoutReturned
+	default:
+		return ""
+	}
+}
+
+// PayoutPoller 后台付款状态轮询
+// 定期查询处理中的付款，以及近期已出款、仍可能被退回的付款
+type PayoutPoller struct {
+	provider payout.Provider
+	interval time.Duration
+	cfg      *config.Config
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewPayoutPoller 根据配置创建付款轮询任务
+func NewPayoutPoller(cfg *config.Config) *PayoutPoller {
+	return &PayoutPoller{
+		provider: PayoutProvider(),
+		interval: cfg.PayoutPollInterval,
+		cfg:      cfg,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start 启动后台轮询，interval<=0时不启动
+func (p *PayoutPoller) Start() {
+	if p.interval <= 0 {
+		fmt.Println("[PayoutPoller] 轮询间隔未配置，付款轮询未启动")
+		close(p.done)
+		return
+	}
+
+	health.RegisterWorker(payoutPollerWorker, p.interval)
+	go func() {
+		defer close(p.done)
+		defer health.WorkerStopped(payoutPollerWorker)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		fmt.Printf("[PayoutPoller] 付款轮询已启动 - 间隔: %s, 通道: %s\n", p.interval, p.provider.Name())
+		for {
+			health.Beat(payoutPollerWorker)
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.RunOnce()
+			}
+		}
+	}()
+}
+
+// Stop 停止后台轮询并等待当前一轮结束
+func (p *PayoutPoller) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+// RunOnce 执行一轮轮询
+func (p *PayoutPoller) RunOnce() {
+	now := time.Now()
+	currentEnv := string(p.cfg.GetCurrentAPIEnv())
+	// 当前环境的密钥无法查询另一环境的付款
+	due := payout.Payouts().Filter(func(po models.Payout) bool {
+		if po.Environment != currentEnv || po.Provider != p.provider.Name() {
+			return false
+		}
+		return po.Status == models.PayoutProcessing ||
+			(po.Status == models.PayoutPaid && po.CompletedAt != nil && now.Sub(*po.CompletedAt) < payoutReturnWindow)
+	})
+
+	for _, po := range due {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+		if _, err := payout.Refresh(po.ID, p.provider); err != nil {
+			fmt.Printf("[PayoutPoller] 查询失败 - payoutId: %s, error: %v\n", po.ID, err)
+		}
+		health.Beat(payoutPollerWorker)
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/health"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+)
+
+const reconcilerWorker = "reconciler"
+
+// StatusReconciler 后台状态对账任务
+// 定期扫描长时间停留在pending/authorized的本地支付记录和结果未知的退款，主动向Evonet查询最新状态，
+// 避免webhook丢失时支付永远停留在pending
+type StatusReconciler struct {
+	service      *PaymentService
+	interval     time.Duration
+	pendingAfter time.Duration
+	expiry       time.Duration
+	ratePerSec   int
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewStatusReconciler 根据配置创建对账任务
+func NewStatusReconciler(cfg *config.Config) *StatusReconciler {
+	return &StatusReconciler{
+		service:      NewPaymentService(),
+		interval:     cfg.ReconcilerInterval,
+		pendingAfter: cfg.ReconcilerPendingAfter,
+		expiry:       cfg.InteractionExpiry,
+		ratePerSec:   cfg.ReconcilerRatePerSec,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start 启动后台扫描，interval<=0时不启动
+func (r *StatusReconciler) Start() {
+	if r.interval <= 0 {
+		fmt.Println("[Reconciler] 扫描间隔未配置，对账任务未启动")
+		close(r.done)
+		return
+	}
+
+	health.RegisterWorker(reconcilerWorker, r.interval)
+	go func() {
+		defer close(r.done)
+		defer health.WorkerStopped(reconcilerWorker)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		fmt.Printf("[Reconciler] 对账任务已启动 - 间隔: %s, 阈值: %s\n", r.interval, r.pendingAfter)
+		for {
+			health.Beat(reconcilerWorker)
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.RunOnce()
+			}
+		}
+	}()
+}
+
+// Stop 停止后台扫描并等待当前一轮结束
+func (r *StatusReconciler) Stop() {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+}
+
+// RunOnce 执行一轮扫描
+func (r *StatusReconciler) RunOnce() {
+	now := time.Now()
+	stale := store.Payments().Filter(func(p models.PaymentRecord) bool {
+		if p.Status != models.StatusPending && p.Status != models.StatusAuthorized {
+			return false
+		}
+		return now.Sub(p.UpdatedAt) >= r.pendingAfter
+	})
+	staleRefunds := store.Refunds().Filter(func(refund models.Refund) bool {
+		return refund.Status == models.RefundPending && now.Sub(refund.UpdatedAt) >= r.pendingAfter
+	})
+	if len(stale) == 0 && len(staleRefunds) == 0 {
+		return
+	}
+	fmt.Printf("[Reconciler] 发现 %d 笔待对账支付, %d 笔待确认退款\n", len(stale), len(staleRefunds))
+
+	// 简单的令牌节流，避免对Evonet造成突发压力
+	var throttle <-chan time.Time
+	if r.ratePerSec > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(r.ratePerSec))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	queried := 0
+	wait := func() bool {
+		if throttle != nil && queried > 0 {
+			select {
+			case <-r.stop:
+				return false
+			case <-throttle:
+			}
+		}
+		queried++
+		return true
+	}
+
+	currentEnv := string(r.service.config.GetCurrentAPIEnv())
+	for _, record := range stale {
+		// 当前环境的密钥无法查询另一环境的订单
+		if record.Environment != currentEnv {
+			continue
+		}
+		if !wait() {
+			return
+		}
+		r.reconcile(record)
+		health.Beat(reconcilerWorker) // 单轮可能较长，逐笔上报心跳
+	}
+	for _, refund := range staleRefunds {
+		if refund.Environment != currentEnv {
+			continue
+		}
+		if !wait() {
+			return
+		}
+		r.reconcileRefund(refund)
+		health.Beat(reconcilerWorker)
+	}
+}
+
+// reconcileRefund 查询结果未知的退款，成功或失败时更新退款和支付记录
+func (r *StatusReconciler) reconcileRefund(refund models.Refund) {
+	status, message, err := r.service.queryRefundStatus(refund)
+	if err != nil {
+		fmt.Printf("[Reconciler] 退款查询失败 - refundID: %s, error: %v\n", refund.RefundID, err)
+		return
+	}
+	if status == models.RefundPending {
+		// 刷新UpdatedAt，下次间隔pendingAfter后再查
+		_, _ = store.Refunds().Update(refund.RefundID, func(r *models.Refund) error {
+			r.UpdatedAt = time.Now()
+			return nil
+		})
+		return
+	}
+	if _, err := r.service.ResolveRefund(refund.RefundID, status, message); err != nil {
+		fmt.Printf("[Reconciler] 退款状态更新失败 - refundID: %s, error: %v\n", refund.RefundID, err)
+	}
+}
+
+// reconcile 查询单笔支付并通过状态机应用变化
+func (r *StatusReconciler) reconcile(record models.PaymentRecord) {
+	var (
+		payment *models.Payment
+		err     error
+	)
+	if record.IsInteraction() {
+		payment, err = r.service.queryRealInteractionStatus(record.MerchantTransID)
+	} else {
+		payment, err = r.service.queryRealPaymentStatus(record.MerchantTransID)
+	}
+	if err != nil {
+		fmt.Printf("[Reconciler] 查询失败 - merchantTransID: %s, error: %v\n", record.MerchantTransID, err)
+		return
+	}
+
+	status := payment.Status
+	// 交互超过有效期仍未完成，标记为过期
+	if record.IsInteraction() && r.expiry > 0 && time.Since(record.CreatedAt) >= r.expiry &&
+		(status == models.StatusPending || status == models.StatusUnknown) {
+		status = models.StatusExpired
+	}
+
+	if _, err := transitionPayment(record.MerchantTransID, status, "reconciler"); err != nil {
+		fmt.Printf("[Reconciler] 状态更新失败 - merchantTransID: %s, error: %v\n", record.MerchantTransID, err)
+	}
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"payment-demo/internal/fees"
+	"payment-demo/internal/ledger"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+// ErrRefundNotAllowed 支付当前状态或剩余金额不允许退款
+var ErrRefundNotAllowed = errors.New("refund not allowed")
+
+// ErrEnvironmentMismatch 支付属于另一Evonet环境，当前环境的密钥无法操作
+var ErrEnvironmentMismatch = errors.New("payment belongs to another environment")
+
+// evonetRefundResponse Evonet退款及退款查询接口的响应
+type evonetRefundResponse struct {
+	Result struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"result"`
+	Refund struct {
+		Status string `json:"status"`
+	} `json:"refund"`
+}
+
+// RefundPayment 对已扣款的支付发起（部分）退款
+// 退款金额在调用Evonet之前预留到pendingRefundAmount，避免并发退款超过可退金额；
+// 退款成功后转入refundedAmount，失败时释放，结果未知（pending或请求超时）时保留预留，由对账任务查询结果
+func (s *PaymentService) RefundPayment(merchantTransID string, req *models.RefundRequest) (*models.Refund, error) {
+	currentEnv := string(s.config.GetCurrentAPIEnv())
+	payment, err := store.Payments().Update(merchantTransID, func(p *models.PaymentRecord) error {
+		// 沙箱支付不能用生产密钥退款，反之亦然
+		if p.Environment != currentEnv {
+			return fmt.Errorf("%w: payment is %s, current environment is %s", ErrEnvironmentMismatch, p.Environment, currentEnv)
+		}
+		if p.Status != models.StatusCaptured && p.Status != models.StatusPartiallyRefunded {
+			return fmt.Errorf("%w: payment status is %s", ErrRefundNotAllowed, p.Status)
+		}
+		if remaining := p.Amount - p.RefundedAmount - p.PendingRefundAmount; req.Amount > remaining {
+			return fmt.Errorf("%w: amount exceeds refundable %.0f", ErrRefundNotAllowed, remaining)
+		}
+		p.PendingRefundAmount += req.Amount
+		p.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refundID := utils.GenerateMerchantTransID("refund_")
+	evonetReq := map[string]interface{}{
+		"merchantTransInfo": map[string]interface{}{
+			"merchantTransID":   refundID,
+			"merchantTransTime": time.Now().Format("2006-01-02T15:04:05+08:00"),
+		},
+		"transAmount": map[string]interface{}{
+			"currency": payment.Currency,
+			"value":    utils.FormatAmount(req.Amount),
+		},
+	}
+
+	status := models.RefundPending
+	var message string
+	resp, err := s.sendEvonetRequest("POST", fmt.Sprintf("/payment/%s/refund", url.PathEscape(merchantTransID)), evonetReq)
+	if errors.Is(err, ErrCircuitOpen) {
+		// 熔断时请求未发送，释放预留后可直接重试
+		s.releaseRefund(merchantTransID, req.Amount)
+		return nil, err
+	}
+	if err != nil {
+		// 请求可能已被Evonet受理，保留预留金额，等对账任务查询结果
+		fmt.Printf("[PaymentService] 退款请求结果未知 - refundID: %s, error: %v\n", refundID, err)
+		message = err.Error()
+	} else {
+		var evonetResp evonetRefundResponse
+		if err := json.Unmarshal(resp, &evonetResp); err != nil {
+			fmt.Printf("[PaymentService] 解析退款响应失败 - refundID: %s, error: %v\n", refundID, err)
+			message = err.Error()
+		} else {
+			status = refundStatus(evonetResp.Result.Code, evonetResp.Refund.Status)
+			message = evonetResp.Result.Message
+		}
+	}
+
+	now := time.Now()
+	refund := models.Refund{
+		RefundID:        refundID,
+		MerchantTransID: merchantTransID,
+		Amount:          req.Amount,
+		Currency:        payment.Currency,
+		Status:          models.RefundPending,
+		Reason:          req.Reason,
+		PaymentMethod:   payment.PaymentMethod,
+		Environment:     payment.Environment,
+		Message:         message,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := store.Refunds().Insert(refund.RefundID, refund); err != nil {
+		s.releaseRefund(merchantTransID, req.Amount)
+		return nil, fmt.Errorf("failed to save refund: %w", err)
+	}
+
+	if status == models.RefundPending {
+		return &refund, nil
+	}
+	resolved, err := s.ResolveRefund(refundID, status, message)
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// ResolveRefund 将pending退款更新为最终状态：成功时计算退款手续费、计入已退款金额并记账，失败时释放预留金额
+// 退款已不是pending时直接返回当前记录
+func (s *PaymentService) ResolveRefund(refundID, status, message string) (*models.Refund, error) {
+	if status != models.RefundSucceeded && status != models.RefundFailed {
+		refund, ok := store.Refunds().Get(refundID)
+		if !ok {
+			return nil, store.ErrNotFound
+		}
+		return &refund, nil
+	}
+
+	var resolved bool
+	refund, err := store.Refunds().Update(refundID, func(r *models.Refund) error {
+		if r.Status != models.RefundPending {
+			return nil
+		}
+		resolved = true
+		r.Status = status
+		if message != "" {
+			r.Message = message
+		}
+		r.UpdatedAt = time.Now()
+		if status == models.RefundSucceeded {
+			payment, ok := store.Payments().Get(r.MerchantTransID)
+			if !ok {
+				return nil
+			}
+			var err error
+			if r.Fees, err = fees.Default().ForRefund(payment, r.Amount); err != nil {
+				fmt.Printf("[PaymentService] 计算退款手续费失败 - refundID: %s, error: %v\n", r.RefundID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return &refund, nil
+	}
+
+	fmt.Printf("[PaymentService] 退款%s - refundID: %s, merchantTransID: %s\n", refund.Status, refund.RefundID, refund.MerchantTransID)
+	if refund.Status == models.RefundSucceeded {
+		s.applyRefund(refund.MerchantTransID, &refund)
+		ledger.RecordRefund(refund)
+	} else {
+		s.releaseRefund(refund.MerchantTransID, refund.Amount)
+	}
+	return &refund, nil
+}
+
+// queryRefundStatus 向Evonet查询退款结果
+func (s *PaymentService) queryRefundStatus(refund models.Refund) (string, string, error) {
+	resp, err := s.sendEvonetRequest("GET", fmt.Sprintf("/payment/%s/refund/%s", url.PathEscape(refund.MerchantTransID), url.PathEscape(refund.RefundID)), nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to query refund status: %w", err)
+	}
+	var evonetResp evonetRefundResponse
+	if err := json.Unmarshal(resp, &evonetResp); err != nil {
+		return "", "", fmt.Errorf("failed to parse refund status response: %w", err)
+	}
+	return refundStatus(evonetResp.Result.Code, evonetResp.Refund.Status), evonetResp.Result.Message, nil
+}
+
+// applyRefund 将预留金额转为已退款金额，累加退款手续费，更新净额并通过状态机更新支付状态
+func (s *PaymentService) applyRefund(merchantTransID string, refund *models.Refund) {
+	payment, err := store.Payments().Update(merchantTransID, func(p *models.PaymentRecord) error {
+		p.PendingRefundAmount = max(p.PendingRefundAmount-refund.Amount, 0)
+		p.RefundedAmount += refund.Amount
+		if refund.Fees != nil {
+			p.RefundFees += refund.Fees.Total
+		}
+		p.NetAmount = fees.NetAmount(*p)
+		p.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("[PaymentService] 更新已退款金额失败 - merchantTransID: %s, error: %v\n", merchantTransID, err)
+		return
+	}
+
+	status := models.StatusPartiallyRefunded
+	if payment.RefundedAmount >= payment.Amount {
+		status = models.StatusRefunded
+	}
+	s.applyQueriedStatus(merchantTransID, status, "refund")
+}
+
+// releaseRefund 释放失败退款的预留金额
+func (s *PaymentService) releaseRefund(merchantTransID string, amount float64) {
+	_, err := store.Payments().Update(merchantTransID, func(p *models.PaymentRecord) error {
+		p.PendingRefundAmount = max(p.PendingRefundAmount-amount, 0)
+		p.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("[PaymentService] 释放退款预留金额失败 - merchantTransID: %s, error: %v\n", merchantTransID, err)
+	}
+}
+
+// refundStatus 将Evonet的结果码和退款状态映射为本地退款状态
+func refundStatus(code, status string) string {
+	if code == "" || code[0] != 'S' {
+		return models.RefundFailed
+	}
+	switch strings.ToLower(status) {
+	case "success", "succeeded", "completed", "refunded":
+		return models.RefundSucceeded
+	case "failed", "declined", "rejected":
+		return models.RefundFailed
+	default:
+		return models.RefundPending
+	}
+}
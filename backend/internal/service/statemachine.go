@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"payment-demo/internal/audit"
+	"payment-demo/internal/ledger"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/webhook"
+)
+
+// ErrInvalidTransition 非法的状态流转
+var ErrInvalidTransition = errors.New("invalid payment status transition")
+
+// allowedTransitions 允许的支付状态流转
+// expired之后仍允许流转到captured，以接住过期后才到达的成功结果
+var allowedTransitions = map[string][]string{
+	models.StatusPending:    {models.StatusAuthorized, models.StatusCaptured, models.StatusFailed, models.StatusCancelled, models.StatusExpired},
+	models.StatusAuthorized: {models.StatusCaptured, models.StatusFailed, models.StatusCancelled, models.StatusExpired},
+	models.StatusExpired:    {models.StatusCaptured},
+	models.StatusCaptured:   {models.StatusPartiallyRefunded, models.StatusRefunded},
+
+	models.StatusPartiallyRefunded: {models.StatusRefunded},
+}
+
+// CanTransition 判断支付状态能否从from流转到to
+func CanTransition(from, to string) bool {
+	for _, next := range allowedTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransition 一次已生效的状态变更
+type StatusTransition struct {
+	Record models.PaymentRecord
+	From   string
+	To     string
+	Source string // 触发来源：query, reconciler, webhook等
+}
+
+// transitionPayment 通过状态机更新本地支付记录的状态
+// 状态未变化或本地无记录时返回nil；非法流转返回ErrInvalidTransition
+func transitionPayment(merchantTransID, status, source string) (*StatusTransition, error) {
+	if status == "" || status == models.StatusUnknown {
+		return nil, nil
+	}
+
+	var from string
+	record, err := store.Payments().Update(merchantTransID, func(p *models.PaymentRecord) error {
+		from = p.Status
+		now := time.Now()
+		p.LastCheckedAt = &now
+		if p.Status == status {
+			return nil
+		}
+		if !CanTransition(p.Status, status) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, p.Status, status)
+		}
+		p.Status = status
+		p.UpdatedAt = now
+		if status == models.StatusCaptured {
+			p.CapturedAt = &now
+			applyCaptureFees(p)
+		}
+		return nil
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if from == status {
+		return nil, nil
+	}
+
+	fmt.Printf("[StateMachine] %s: %s -> %s (来源: %s)\n", merchantTransID, from, status, source)
+	transition := &StatusTransition{Record: record, From: from, To: status, Source: source}
+	if status == models.StatusCaptured {
+		ledger.RecordCapture(record)
+	}
+	onTransition(transition)
+	return transition, nil
+}
+
+// onTransition 状态变更后的副作用：写审计日志、通知商户webhook
+func onTransition(t *StatusTransition) {
+	audit.Record(audit.Event{
+		Actor:      "system:" + t.Source,
+		Action:     audit.ActionPaymentTransition,
+		TargetType: "payment",
+		TargetID:   t.Record.MerchantTransID,
+		Before:     map[string]string{"status": t.From},
+		After:      map[string]string{"status": t.To},
+	})
+
+	event := map[string]interface{}{
+		"payment":        t.Record,
+		"previousStatus": t.From,
+		"source":         t.Source,
+	}
+	if err := webhook.Publish("payment."+t.To, event); err != nil {
+		fmt.Printf("[StateMachine] 商户webhook入队失败 - merchantTransID: %s, error: %v\n", t.Record.MerchantTransID, err)
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/dispute"
+	"payment-demo/internal/health"
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+// ErrInvalidWebhookPayload webhook请求体无法解析
+var ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+
+// inboundNotify 新事件入库时唤醒处理任务
+var inboundNotify = make(chan struct{}, 1)
+
+// WebhookEvents 入站webhook事件日志
+func WebhookEvents() *store.Collection[models.InboundWebhookEvent] {
+	return store.Open[models.InboundWebhookEvent]("webhook_events")
+}
+
+// RecordWebhookEvent 原样保存入站webhook并按事件ID或merchantTransId+eventCode去重
+// 重复事件只累加计数，返回已有记录且duplicate为true；签名校验失败的事件以随机ID记为rejected，不参与去重也不会被处理
+func RecordWebhookEvent(header http.Header, body []byte) (event models.InboundWebhookEvent, duplicate bool, err error) {
+	cfg := config.Load()
+	event = models.InboundWebhookEvent{
+		Headers:    maskWebhookHeaders(header),
+		Body:       string(body),
+		Status:     models.InboundReceived,
+		ReceivedAt: time.Now(),
+	}
+	if err := verifyEvonetSignature(cfg, header); err != nil {
+		event.VerificationError = err.Error()
+	} else {
+		event.SignatureValid = true
+	}
+
+	var notification models.WebhookNotification
+	parseErr := json.Unmarshal(body, &notification)
+	if parseErr == nil {
+		event.EventID = notification.EventID
+		if event.EventID == "" {
+			event.EventID = header.Get("Event-Id")
+		}
+		event.EventCode = notification.EventCode
+		if notification.Payment != nil {
+			event.MerchantTransID = notification.Payment.MerchantTransID
+		}
+		if notification.Dispute != nil {
+			event.DisputeID = notification.Dispute.DisputeID
+			if event.MerchantTransID == "" {
+				event.MerchantTransID = notification.Dispute.MerchantTransID
+			}
+		}
+	}
+
+	event.DedupKey = webhookDedupKey(event, body)
+	if event.SignatureValid {
+		sum := sha256.Sum256([]byte(event.DedupKey))
+		event.ID = "whe_" + hex.EncodeToString(sum[:12])
+	} else {
+		// 未签名事件不占用去重键，否则伪造的事件ID或merchantTransId+eventCode会挡住随后到达的真实通知
+		event.ID = utils.GenerateID("whe")
+	}
+
+	switch {
+	case parseErr != nil:
+		event.Status = models.InboundFailed
+		event.Result = parseErr.Error()
+	case !event.SignatureValid:
+		event.Status = models.InboundRejected
+		event.Result = "signature verification failed"
+	}
+
+	err = WebhookEvents().Insert(event.ID, event)
+	if errors.Is(err, store.ErrDuplicate) {
+		existing, updateErr := WebhookEvents().Update(event.ID, func(e *models.InboundWebhookEvent) error {
+			e.DuplicateCount++
+			return nil
+		})
+		return existing, true, updateErr
+	}
+	if err != nil {
+		return event, false, err
+	}
+
+	if parseErr != nil {
+		return event, false, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, parseErr)
+	}
+	if event.Status == models.InboundReceived {
+		select {
+		case inboundNotify <- struct{}{}:
+		default:
+		}
+	}
+	return event, false, nil
+}
+
+// ReplayWebhookEvent 将已保存的事件重新送入处理流程（同步执行）
+func ReplayWebhookEvent(id string) (models.InboundWebhookEvent, error) {
+	event, ok := WebhookEvents().Get(id)
+	if !ok {
+		return event, store.ErrNotFound
+	}
+	return processWebhookEvent(event)
+}
+
+// webhookDedupKey 优先使用事件ID，其次merchantTransId+eventCode，都没有时退化为请求体摘要
+// 同一拒付会多次收到DISPUTE_UPDATED，因此拒付通知额外区分请求体
+func webhookDedupKey(event models.InboundWebhookEvent, body []byte) string {
+	if event.EventID != "" {
+		return "event:" + event.EventID
+	}
+	sum := sha256.Sum256(body)
+	if event.DisputeID != "" {
+		return "dispute:" + event.DisputeID + ":" + event.EventCode + ":" + hex.EncodeToString(sum[:8])
+	}
+	if event.MerchantTransID != "" && event.EventCode != "" {
+		return "trans:" + event.MerchantTransID + ":" + event.EventCode
+	}
+	return "body:" + hex.EncodeToString(sum[:])
+}
+
+// verifyEvonetSignature 校验Key-based签名：Authorization需与当前环境的SignKey一致
+func verifyEvonetSignature(cfg *config.Config, header http.Header) error {
+	signKey := cfg.GetCurrentEvonetConfig().SignKey
+	auth := header.Get("Authorization")
+	if auth == "" {
+		return errors.New("missing Authorization header")
+	}
+	if subtle.ConstantTimeCompare([]byte(auth), []byte(signKey)) != 1 {
+		return errors.New("authorization does not match sign key")
+	}
+	return nil
+}
+
+// processWebhookEvent 解析事件并通过状态机更新本地支付记录
+func processWebhookEvent(event models.InboundWebhookEvent) (models.InboundWebhookEvent, error) {
+	status, result, procErr := applyWebhookEvent(event)
+
+	return WebhookEvents().Update(event.ID, func(e *models.InboundWebhookEvent) error {
+		now := time.Now()
+		e.Attempts++
+		e.Status = status
+		e.Result = result
+		e.ProcessedAt = &now
+		if procErr != nil {
+			e.Result = procErr.Error()
+		}
+		return nil
+	})
+}
+
+// applyWebhookEvent 返回事件处理后的状态和说明
+func applyWebhookEvent(event models.InboundWebhookEvent) (string, string, error) {
+	// 未通过签名校验的事件只记录，不改变支付和拒付状态
+	if !event.SignatureValid {
+		return models.InboundRejected, "signature verification failed", nil
+	}
+
+	var notification models.WebhookNotification
+	if err := json.Unmarshal([]byte(event.Body), &notification); err != nil {
+		return models.InboundFailed, "", fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+	if notification.Dispute != nil || dispute.IsDisputeEvent(notification.EventCode) {
+		return applyDisputeEvent(&notification)
+	}
+	if notification.Payment == nil || notification.Payment.MerchantTransID == "" {
+		return models.InboundIgnored, "notification has no payment", nil
+	}
+
+	status := normalizeStatus(notification.Payment.Status)
+	transition, err := transitionPayment(notification.Payment.MerchantTransID, status, "webhook")
+	if errors.Is(err, ErrInvalidTransition) {
+		// 乱序到达的旧事件不能让状态回退
+		return models.InboundIgnored, err.Error(), nil
+	}
+	if err != nil {
+		return models.InboundFailed, "", err
+	}
+	if transition == nil {
+		return models.InboundIgnored, "no local payment or status unchanged", nil
+	}
+	return models.InboundProcessed, fmt.Sprintf("%s -> %s", transition.From, transition.To), nil
+}
+
+// applyDisputeEvent 创建或更新拒付记录，支付状态不变
+func applyDisputeEvent(notification *models.WebhookNotification) (string, string, error) {
+	n := notification.Dispute
+	if n == nil {
+		return models.InboundIgnored, "dispute event has no dispute details", nil
+	}
+	if n.MerchantTransID == "" && notification.Payment != nil {
+		n.MerchantTransID = notification.Payment.MerchantTransID
+	}
+
+	_, result, err := dispute.Apply(notification.EventCode, n)
+	switch {
+	case errors.Is(err, dispute.ErrInvalidNotification):
+		return models.InboundFailed, "", err
+	case errors.Is(err, dispute.ErrStaleNotification):
+		return models.InboundIgnored, err.Error(), nil
+	case err != nil:
+		return models.InboundFailed, "", err
+	}
+	return models.InboundProcessed, "dispute " + n.DisputeID + ": " + result, nil
+}
+
+const webhookEventsWorker = "webhook-events"
+
+// WebhookEventProcessor 异步处理入站webhook事件的后台任务
+type WebhookEventProcessor struct {
+	pollInterval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewWebhookEventProcessor 创建入站事件处理任务
+func NewWebhookEventProcessor() *WebhookEventProcessor {
+	return &WebhookEventProcessor{
+		pollInterval: 5 * time.Second,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start 启动后台处理
+func (p *WebhookEventProcessor) Start() {
+	health.RegisterWorker(webhookEventsWorker, p.pollInterval)
+	go func() {
+		defer close(p.done)
+		defer health.WorkerStopped(webhookEventsWorker)
+		ticker := time.NewTicker(p.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			health.Beat(webhookEventsWorker)
+			p.processPending()
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			case <-inboundNotify:
+			}
+		}
+	}()
+}
+
+// Stop 停止后台处理并等待当前事件处理完成
+func (p *WebhookEventProcessor) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+// processPending 按接收顺序处理所有待处理事件
+func (p *WebhookEventProcessor) processPending() {
+	pending := WebhookEvents().Filter(func(e models.InboundWebhookEvent) bool {
+		return e.Status == models.InboundReceived
+	})
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].ReceivedAt.Before(pending[j].ReceivedAt)
+	})
+
+	for _, event := range pending {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+		if _, err := processWebhookEvent(event); err != nil {
+			fmt.Printf("[WebhookEvents] 处理事件失败 - id: %s, error: %v\n", event.ID, err)
+		}
+	}
+}
+
+// maskWebhookHeaders 保存前隐去Authorization中的签名密钥
+func maskWebhookHeaders(header http.Header) map[string]string {
+	masked := make(map[string]string, len(header))
+	for key := range header {
+		value := header.Get(key)
+		if strings.EqualFold(key, "Authorization") && value != "" {
+			value = "***"
+		}
+		masked[key] = value
+	}
+	return masked
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"payment-demo/config"
+	"payment-demo/internal/models"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "payment-demo-service-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create data dir: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("DATA_DIR", dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// 伪造的未签名事件先到达时，不能占用去重键挡住随后到达的真实通知
+func TestForgedWebhookDoesNotBlockGenuineEvent(t *testing.T) {
+	signKey := config.Load().GetCurrentEvonetConfig().SignKey
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"eventId", `{"eventId":"evt-forged-1","eventCode":"PAYMENT_UPDATED","payment":{"merchantTransId":"mt-forged-1","status":"Failed"}}`},
+		{"merchantTransId+eventCode", `{"eventCode":"PAYMENT_UPDATED","payment":{"merchantTransId":"mt-forged-2","status":"Failed"}}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			forged, duplicate, err := RecordWebhookEvent(http.Header{"Authorization": {"forged"}}, []byte(tc.body))
+			if err != nil || duplicate {
+				t.Fatalf("forged event: duplicate=%v err=%v", duplicate, err)
+			}
+			if forged.Status != models.InboundRejected {
+				t.Fatalf("forged event status = %s, want %s", forged.Status, models.InboundRejected)
+			}
+
+			genuine, duplicate, err := RecordWebhookEvent(http.Header{"Authorization": {signKey}}, []byte(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if duplicate || genuine.ID == forged.ID {
+				t.Fatalf("genuine event was deduplicated against forged event %s", forged.ID)
+			}
+			if genuine.Status != models.InboundReceived {
+				t.Fatalf("genuine event status = %s, want %s", genuine.Status, models.InboundReceived)
+			}
+
+			// 真实通知重发时仍按去重键合并
+			again, duplicate, err := RecordWebhookEvent(http.Header{"Authorization": {signKey}}, []byte(tc.body))
+			if err != nil || !duplicate || again.ID != genuine.ID {
+				t.Fatalf("resent genuine event: duplicate=%v id=%s err=%v", duplicate, again.ID, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"payment-demo/internal/models"
+)
+
+// ErrInvalidCursor 分页游标无法解析或与排序条件不匹配
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Payments 本地支付记录集合，以merchantTransId为键
+func Payments() *Collection[models.PaymentRecord] {
+	return Open[models.PaymentRecord]("payments")
+}
+
+// 支持的排序字段
+const (
+	SortByCreatedAt = "createdAt"
+	SortByUpdatedAt = "updatedAt"
+	SortByAmount    = "amount"
+)
+
+// PaymentQuery 支付列表查询条件，零值字段表示不过滤
+type PaymentQuery struct {
+	Status          string
+	PaymentType     string
+	Currency        string
+	Environment     string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	MinAmount       *float64
+	MaxAmount       *float64
+	MerchantTransID string            // 前缀匹配
+	Metadata        map[string]string // 所有键值都需完全匹配
+
+	SortBy string // createdAt（默认）, updatedAt, amount
+	Desc   bool
+	Limit  int
+	Cursor string
+}
+
+// PaymentPage 一页查询结果
+type PaymentPage struct {
+	Items      []models.PaymentRecord
+	NextCursor string
+	HasMore    bool
+}
+
+// paymentCursor 游标记录上一页最后一条的排序值和ID（keyset分页）
+type paymentCursor struct {
+	SortBy string    `json:"s"`
+	Desc   bool      `json:"d"`
+	Time   time.Time `json:"t,omitempty"`
+	Amount float64   `json:"a,omitempty"`
+	ID     string    `json:"id"`
+}
+
+// QueryPayments 按条件过滤、排序并分页返回支付记录
+func QueryPayments(q PaymentQuery) (*PaymentPage, error) {
+	if q.SortBy == "" {
+		q.SortBy = SortByCreatedAt
+	}
+
+	var after *paymentCursor
+	if q.Cursor != "" {
+		cursor, err := decodePaymentCursor(q.Cursor)
+		if err != nil || cursor.SortBy != q.SortBy || cursor.Desc != q.Desc {
+			return nil, ErrInvalidCursor
+		}
+		after = cursor
+	}
+
+	items := Payments().Filter(q.matches)
+	sort.Slice(items, func(i, j int) bool {
+		return comparePayments(q.SortBy, cursorOf(q, items[i]), cursorOf(q, items[j]), q.Desc) < 0
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return comparePayments(q.SortBy, cursorOf(q, items[i]), after, q.Desc) > 0
+		})
+	}
+
+	end := start + q.Limit
+	if q.Limit <= 0 || end > len(items) {
+		end = len(items)
+	}
+
+	page := &PaymentPage{Items: items[start:end], HasMore: end < len(items)}
+	if page.HasMore && end > start {
+		page.NextCursor = encodePaymentCursor(cursorOf(q, items[end-1]))
+	}
+	return page, nil
+}
+
+// matches 判断记录是否满足过滤条件
+func (q PaymentQuery) matches(p models.PaymentRecord) bool {
+	switch {
+	case q.Status != "" && p.Status != q.Status,
+		q.PaymentType != "" && p.PaymentType != q.PaymentType,
+		q.Currency != "" && !strings.EqualFold(p.Currency, q.Currency),
+		q.Environment != "" && p.Environment != q.Environment,
+		!q.CreatedFrom.IsZero() && p.CreatedAt.Before(q.CreatedFrom),
+		!q.CreatedTo.IsZero() && !p.CreatedAt.Before(q.CreatedTo),
+		q.MinAmount != nil && p.Amount < *q.MinAmount,
+		q.MaxAmount != nil && p.Amount > *q.MaxAmount,
+		q.MerchantTransID != "" && !strings.HasPrefix(p.MerchantTransID, q.MerchantTransID):
+		return false
+	}
+	for key, value := range q.Metadata {
+		if v, ok := p.Metadata[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func cursorOf(q PaymentQuery, p models.PaymentRecord) *paymentCursor {
+	c := &paymentCursor{SortBy: q.SortBy, Desc: q.Desc, ID: p.MerchantTransID}
+	switch q.SortBy {
+	case SortByAmount:
+		c.Amount = p.Amount
+	case SortByUpdatedAt:
+		c.Time = p.UpdatedAt
+	default:
+		c.Time = p.CreatedAt
+	}
+	return c
+}
+
+// comparePayments 按排序值比较，值相同时按ID升序保证顺序稳定
+func comparePayments(sortBy string, a, b *paymentCursor, desc bool) int {
+	result := 0
+	if sortBy == SortByAmount {
+		switch {
+		case a.Amount < b.Amount:
+			result = -1
+		case a.Amount > b.Amount:
+			result = 1
+		}
+	} else {
+		result = a.Time.Compare(b.Time)
+	}
+	if desc {
+		result = -result
+	}
+	if result == 0 {
+		result = strings.Compare(a.ID, b.ID)
+	}
+	return result
+}
+
+func encodePaymentCursor(c *paymentCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePaymentCursor(s string) (*paymentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c paymentCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Refunds 退款记录集合，以refundId为键
+func Refunds() *Collection[models.Refund] {
+	return Open[models.Refund]("refunds")
+}
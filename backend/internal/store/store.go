@@ -0,0 +1,287 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+)
+
+var (
+	// ErrNotFound 记录不存在
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate 记录已存在
+	ErrDuplicate = errors.New("record already exists")
+)
+
+// Collection 内存中的键值集合，每次写入后整体持久化为一个JSON文件
+// path为空时仅保存在内存中
+type Collection[T any] struct {
+	mu    sync.RWMutex
+	path  string
+	items map[string]T
+}
+
+// LoadError 加载失败、已退化为空集合的数据文件
+// 原数据仍在隔离文件中，需由运维恢复或确认后才清除，之后的写入不会让就绪检查恢复
+type LoadError struct {
+	Collection    string    `json:"collection"`
+	Error         string    `json:"error"`
+	QuarantinedTo string    `json:"quarantinedTo,omitempty"` // 原文件移至的路径，移动失败时为空
+	FailedAt      time.Time `json:"failedAt"`
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]any{}
+	loadErrors = map[string]LoadError{}
+)
+
+// Open 打开（或复用）数据目录下名为name的集合
+func Open[T any](name string) *Collection[T] {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if c, ok := registry[name]; ok {
+		return c.(*Collection[T])
+	}
+
+	path := ""
+	if dir := config.Load().DataDir; dir != "" {
+		path = filepath.Join(dir, name+".json")
+	}
+
+	c, err := NewCollection[T](path)
+	if err != nil {
+		// 数据文件损坏时不阻止服务启动，退化为空集合；原文件先移走，避免下次写入覆盖
+		fmt.Printf("[Store] 加载集合 %s 失败: %v\n", name, err)
+		writePath, aside := quarantine(path)
+		loadErrors[name] = LoadError{Collection: name, Error: err.Error(), QuarantinedTo: aside, FailedAt: time.Now()}
+		c = &Collection[T]{path: writePath, items: map[string]T{}}
+	}
+	registry[name] = c
+	return c
+}
+
+// quarantine 将无法加载的数据文件重命名为 <name>.json.corrupt-<时间>，返回集合之后可继续写入的路径和隔离文件路径
+// 重命名失败时返回空路径，集合只保存在内存中
+func quarantine(path string) (string, string) {
+	aside := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102T150405"))
+	if err := os.Rename(path, aside); err != nil {
+		fmt.Printf("[Store] 无法移走数据文件 %s，集合仅保存在内存中: %v\n", path, err)
+		return "", ""
+	}
+	fmt.Printf("[Store] 已将无法加载的数据文件移至 %s\n", aside)
+	return path, aside
+}
+
+// LoadErrors 返回尚未确认的加载失败记录
+func LoadErrors() []LoadError {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	list := make([]LoadError, 0, len(loadErrors))
+	for _, e := range loadErrors {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Collection < list[j].Collection })
+	return list
+}
+
+// AcknowledgeLoadError 运维恢复或确认放弃隔离文件中的数据后清除加载失败记录
+func AcknowledgeLoadError(name string) (LoadError, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	e, ok := loadErrors[name]
+	if !ok {
+		return e, ErrNotFound
+	}
+	delete(loadErrors, name)
+	fmt.Printf("[Store] 集合 %s 的加载失败已确认，隔离文件: %s\n", name, e.QuarantinedTo)
+	return e, nil
+}
+
+// Ping 检查数据目录可写，且没有未确认的加载失败
+func Ping() error {
+	registryMu.Lock()
+	for name, e := range loadErrors {
+		registryMu.Unlock()
+		return fmt.Errorf("collection %s failed to load (quarantined to %s): %s", name, e.QuarantinedTo, e.Error)
+	}
+	registryMu.Unlock()
+
+	dir := config.Load().DataDir
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("data dir not available: %w", err)
+	}
+	f, err := os.CreateTemp(dir, ".ping-*")
+	if err != nil {
+		return fmt.Errorf("data dir not writable: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// NewCollection 从path加载集合，文件不存在时返回空集合
+func NewCollection[T any](path string) (*Collection[T], error) {
+	c := &Collection[T]{path: path, items: map[string]T{}}
+	if path == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &c.items); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Get 按ID获取记录
+func (c *Collection[T]) Get(id string) (T, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.items[id]
+	return item, ok
+}
+
+// Insert 新增记录，ID已存在时返回ErrDuplicate
+func (c *Collection[T]) Insert(id string, item T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.items[id]; ok {
+		return ErrDuplicate
+	}
+	c.items[id] = item
+	if err := c.flush(); err != nil {
+		delete(c.items, id)
+		return err
+	}
+	return nil
+}
+
+// Put 新增或覆盖记录
+func (c *Collection[T]) Put(id string, item T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, existed := c.items[id]
+	c.items[id] = item
+	if err := c.flush(); err != nil {
+		if existed {
+			c.items[id] = old
+		} else {
+			delete(c.items, id)
+		}
+		return err
+	}
+	return nil
+}
+
+// Update 在写锁内修改记录，fn返回错误时放弃修改
+func (c *Collection[T]) Update(id string, fn func(item *T) error) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[id]
+	if !ok {
+		var zero T
+		return zero, ErrNotFound
+	}
+	old := item
+	if err := fn(&item); err != nil {
+		return old, err
+	}
+	c.items[id] = item
+	if err := c.flush(); err != nil {
+		c.items[id] = old
+		return old, err
+	}
+	return item, nil
+}
+
+// Delete 删除记录
+func (c *Collection[T]) Delete(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, ok := c.items[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(c.items, id)
+	if err := c.flush(); err != nil {
+		c.items[id] = old
+		return err
+	}
+	return nil
+}
+
+// List 返回所有记录，按ID排序以保证结果稳定
+func (c *Collection[T]) List() []T {
+	return c.Filter(nil)
+}
+
+// Filter 返回满足条件的记录，keep为nil时返回全部
+func (c *Collection[T]) Filter(keep func(item T) bool) []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := make([]string, 0, len(c.items))
+	for id := range c.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := make([]T, 0, len(ids))
+	for _, id := range ids {
+		if keep == nil || keep(c.items[id]) {
+			result = append(result, c.items[id])
+		}
+	}
+	return result
+}
+
+// Len 返回记录数
+func (c *Collection[T]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+// flush 将集合写入临时文件后原子替换，调用方需持有写锁
+func (c *Collection[T]) flush() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c.items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal collection: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create data dir: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", c.path, err)
+	}
+	return nil
+}
@@ -1,35 +1,50 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"math/rand"
+	mrand "math/rand"
+	"sync"
 	"time"
+
+	"github.com/oklog/ulid"
 )
 
-// 生成唯一的商户交易ID
-func GenerateMerchantTransID() string {
-	timestamp := time.Now().Format("20060102150405")
-	random := rand.Intn(999999)
-	return fmt.Sprintf("trans_%s_%06d", timestamp, random)
+var (
+	ulidMu      sync.Mutex
+	ulidEntropy = ulid.Monotonic(rand.Reader, 0)
+)
+
+// 生成唯一的商户交易ID：前缀 + ULID（毫秒时间戳 + 80位随机数，同一毫秒内单调递增）
+func GenerateMerchantTransID(prefix string) string {
+	ulidMu.Lock()
+	defer ulidMu.Unlock()
+	return prefix + ulid.MustNew(ulid.Timestamp(time.Now()), ulidEntropy).String()
 }
 
 // 生成幂等性密钥
 func GenerateIdempotencyKey() string {
 	timestamp := time.Now().Format("20060102150405")
-	random := rand.Intn(999999)
+	random := mrand.Intn(999999)
 	return fmt.Sprintf("idem_%s_%06d", timestamp, random)
 }
 
-// 验证货币代码
-func IsValidCurrency(currency string) bool {
-	validCurrencies := map[string]bool{
-		"USD": true, "HKD": true, "KRW": true, "JPY": true,
-		"MYR": true, "IDR": true, "THB": true, "SGD": true,
-	}
-	return validCurrencies[currency]
-}
-
 // 格式化金额（整数格式）
 func FormatAmount(amount float64) string {
 	return fmt.Sprintf("%.0f", amount)
-}
\ No newline at end of file
+}
+
+// 生成带前缀的随机ID
+func GenerateID(prefix string) string {
+	return prefix + "_" + RandomHex(12)
+}
+
+// 生成n字节的随机十六进制字符串
+func RandomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
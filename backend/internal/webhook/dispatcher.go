@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"payment-demo/config"
+	"payment-demo/internal/health"
+	"payment-demo/internal/models"
+)
+
+// maxBackoff 单次重试的最大等待时长
+const maxBackoff = 6 * time.Hour
+
+// maxAttemptHistory 每条投递保留的尝试记录数
+const maxAttemptHistory = 50
+
+const dispatcherWorker = "webhook-dispatcher"
+
+// Dispatcher 后台投递任务，按指数退避重试失败的投递，重试耗尽后转入死信
+type Dispatcher struct {
+	client       *http.Client
+	maxAttempts  int
+	retryBase    time.Duration
+	pollInterval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewDispatcher 根据配置创建投递任务
+func NewDispatcher(cfg *config.Config) *Dispatcher {
+	return &Dispatcher{
+		client:       &http.Client{Timeout: cfg.WebhookTimeout},
+		maxAttempts:  cfg.WebhookMaxAttempts,
+		retryBase:    cfg.WebhookRetryBase,
+		pollInterval: 5 * time.Second,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start 启动后台投递
+func (d *Dispatcher) Start() {
+	health.RegisterWorker(dispatcherWorker, d.pollInterval)
+	go func() {
+		defer close(d.done)
+		defer health.WorkerStopped(dispatcherWorker)
+		ticker := time.NewTicker(d.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			health.Beat(dispatcherWorker)
+			d.deliverDue()
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+			case <-notify:
+			}
+		}
+	}()
+}
+
+// Stop 停止后台投递并等待当前投递结束
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() { close(d.stop) })
+	<-d.done
+}
+
+// deliverDue 投递所有已到期的待投递记录
+func (d *Dispatcher) deliverDue() {
+	now := time.Now()
+	due := Deliveries().Filter(func(w models.WebhookDelivery) bool {
+		return w.Status == models.DeliveryPending && !w.NextAttemptAt.After(now)
+	})
+
+	for _, delivery := range due {
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+		d.attempt(delivery)
+		health.Beat(dispatcherWorker)
+	}
+}
+
+// attempt 执行一次投递并记录结果
+func (d *Dispatcher) attempt(delivery models.WebhookDelivery) {
+	endpoint, ok := Endpoints().Get(delivery.EndpointID)
+	var statusCode int
+	var deliverErr error
+	if !ok || !endpoint.Active {
+		deliverErr = fmt.Errorf("endpoint %s not found or inactive", delivery.EndpointID)
+	} else {
+		statusCode, deliverErr = d.send(endpoint, delivery)
+	}
+
+	_, err := Deliveries().Update(delivery.ID, func(w *models.WebhookDelivery) error {
+		now := time.Now()
+		w.Attempts++
+		w.RoundAttempts++
+		w.LastStatusCode = statusCode
+		w.UpdatedAt = now
+		record := models.WebhookDeliveryAttempt{
+			Attempt:    w.Attempts,
+			Round:      w.Redeliveries,
+			At:         now,
+			StatusCode: statusCode,
+		}
+		if deliverErr != nil {
+			record.Error = deliverErr.Error()
+		}
+		w.History = append(w.History, record)
+		if len(w.History) > maxAttemptHistory {
+			w.History = w.History[len(w.History)-maxAttemptHistory:]
+		}
+
+		if deliverErr == nil {
+			w.Status = models.DeliverySucceeded
+			w.LastError = ""
+			w.DeliveredAt = &now
+			return nil
+		}
+
+		w.LastError = deliverErr.Error()
+		if w.RoundAttempts >= d.maxAttempts {
+			w.Status = models.DeliveryDead
+			return nil
+		}
+		w.NextAttemptAt = now.Add(d.backoff(w.RoundAttempts))
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("[WebhookDispatcher] 更新投递记录失败 - id: %s, error: %v\n", delivery.ID, err)
+	}
+	if deliverErr != nil {
+		fmt.Printf("[WebhookDispatcher] 投递失败 - id: %s, endpoint: %s, error: %v\n", delivery.ID, delivery.EndpointID, deliverErr)
+	}
+}
+
+// send 发送签名后的事件，2xx视为成功
+func (d *Dispatcher) send(endpoint models.WebhookEndpoint, delivery models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", delivery.ID)
+	req.Header.Set("X-Webhook-Event", delivery.Event.Type)
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff 第n次失败后的等待时长：retryBase * 2^(n-1)
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.retryBase
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	return wait
+}
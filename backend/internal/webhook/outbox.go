@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"payment-demo/internal/models"
+	"payment-demo/internal/store"
+	"payment-demo/internal/utils"
+)
+
+// ErrInvalidEndpoint endpoint参数不合法
+var ErrInvalidEndpoint = errors.New("invalid webhook endpoint")
+
+// notify 新投递入队时唤醒投递任务
+var notify = make(chan struct{}, 1)
+
+// Endpoints 商户webhook接收地址集合
+func Endpoints() *store.Collection[models.WebhookEndpoint] {
+	return store.Open[models.WebhookEndpoint]("webhook_endpoints")
+}
+
+// Deliveries 投递记录集合（即outbox）
+func Deliveries() *store.Collection[models.WebhookDelivery] {
+	return store.Open[models.WebhookDelivery]("webhook_deliveries")
+}
+
+// CreateEndpoint 注册webhook接收地址，未指定secret时自动生成
+func CreateEndpoint(endpointURL string, events []string, description, secret string) (models.WebhookEndpoint, error) {
+	u, err := url.Parse(endpointURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.WebhookEndpoint{}, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidEndpoint)
+	}
+	if secret == "" {
+		secret = "whsec_" + utils.RandomHex(24)
+	}
+
+	endpoint := models.WebhookEndpoint{
+		ID:          utils.GenerateID("we"),
+		URL:         endpointURL,
+		Secret:      secret,
+		Events:      events,
+		Description: description,
+		Active:      true,
+		CreatedAt:   time.Now(),
+	}
+	if err := Endpoints().Insert(endpoint.ID, endpoint); err != nil {
+		return models.WebhookEndpoint{}, err
+	}
+	return endpoint, nil
+}
+
+// Publish 为订阅了该事件的所有endpoint生成投递记录
+func Publish(eventType string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	now := time.Now()
+	event := models.WebhookEvent{
+		ID:        utils.GenerateID("evt"),
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      raw,
+	}
+
+	endpoints := Endpoints().Filter(func(e models.WebhookEndpoint) bool {
+		return e.Active && subscribes(e, eventType)
+	})
+	for _, endpoint := range endpoints {
+		delivery := models.WebhookDelivery{
+			ID:            utils.GenerateID("wd"),
+			EndpointID:    endpoint.ID,
+			Event:         event,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := Deliveries().Insert(delivery.ID, delivery); err != nil {
+			return fmt.Errorf("failed to enqueue delivery: %w", err)
+		}
+	}
+
+	if len(endpoints) > 0 {
+		wake()
+	}
+	return nil
+}
+
+// Redeliver 将投递重新放回队列（包括已成功和死信中的投递），开始新一轮重试，累计次数和历史保留
+func Redeliver(id string) (models.WebhookDelivery, error) {
+	delivery, err := Deliveries().Update(id, func(d *models.WebhookDelivery) error {
+		d.Status = models.DeliveryPending
+		d.Redeliveries++
+		d.RoundAttempts = 0
+		d.NextAttemptAt = time.Now()
+		d.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return delivery, err
+	}
+	wake()
+	return delivery, nil
+}
+
+// subscribes 判断endpoint是否订阅了事件
+func subscribes(e models.WebhookEndpoint, eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == eventType || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func wake() {
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
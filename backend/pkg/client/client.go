@@ -0,0 +1,208 @@
+// Package client 是 Payment Demo 后端的Go客户端，供内部服务调用
+//
+// 请求响应类型（types_gen.go）和接口方法（endpoints_gen.go）由 cmd/clientgen 根据
+// internal/api/openapi.yaml 生成，修改接口定义后执行 go generate ./pkg/client 更新。
+// 需要管理令牌的接口会自动携带 WithAdminToken 设置的令牌。
+package client
+
+//go:generate go run ../../cmd/clientgen -spec ../../internal/api/openapi.yaml -out .
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client 后端API客户端
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	adminToken string
+}
+
+// Option 客户端配置项
+type Option func(*Client)
+
+// WithHTTPClient 使用自定义的http.Client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithAdminToken 设置调用管理接口所需的ADMIN_TOKEN
+func WithAdminToken(token string) Option {
+	return func(c *Client) { c.adminToken = token }
+}
+
+// New 创建客户端，baseURL如 http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError 后端返回的非2xx响应
+type APIError struct {
+	StatusCode int          `json:"-"`
+	Message    string       `json:"message"`
+	Detail     string       `json:"error"`
+	Errors     []FieldError `json:"errors"`
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("payment-demo: %d %s", e.StatusCode, e.Message)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// envelope 统一响应结构 {"success", "data", ...}
+type envelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// request 一次接口调用，json非nil时编码为JSON请求体，否则发送body
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	json        interface{}
+	body        io.Reader
+	contentType string
+	admin       bool // 需要管理令牌
+}
+
+// call 发送请求并将整个响应体解析到out，out为nil时丢弃响应体
+func (c *Client) call(ctx context.Context, r request, out interface{}) error {
+	resp, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// callData 发送请求并将envelope中的data解析到out
+func (c *Client) callData(ctx context.Context, r request, out interface{}) error {
+	var env envelope
+	if err := c.call(ctx, r, &env); err != nil {
+		return err
+	}
+	if out == nil || len(env.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("failed to decode data: %w", err)
+	}
+	return nil
+}
+
+// stream 发送请求并将响应体原样写入w（文件下载、报表导出等）
+func (c *Client) stream(ctx context.Context, r request, w io.Writer) error {
+	resp, err := c.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// send 发送请求，非2xx时返回*APIError；调用方负责关闭响应体
+func (c *Client) send(ctx context.Context, r request) (*http.Response, error) {
+	body, contentType := r.body, r.contentType
+	if r.json != nil {
+		data, err := json.Marshal(r.json)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body, contentType = bytes.NewReader(data), "application/json"
+	}
+
+	u := c.baseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if r.admin && c.adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.adminToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	return nil, apiErr
+}
+
+// formField multipart表单中的一个字段，file非nil时作为文件上传
+type formField struct {
+	name     string
+	value    string
+	file     io.Reader
+	fileName string
+}
+
+// encodeForm 编码multipart表单，跳过空字段
+func encodeForm(fields []formField) (io.Reader, string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for _, f := range fields {
+		switch {
+		case f.file != nil:
+			part, err := form.CreateFormFile(f.name, f.fileName)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := io.Copy(part, f.file); err != nil {
+				return nil, "", err
+			}
+		case f.value != "":
+			if err := form.WriteField(f.name, f.value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, form.FormDataContentType(), nil
+}
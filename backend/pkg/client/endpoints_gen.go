@@ -0,0 +1,855 @@
+// Code generated by clientgen from internal/api/openapi.yaml. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// GetCountries 支持的国家列表
+func (c *Client) GetCountries(ctx context.Context, params GetCountriesParams) ([]Country, error) {
+	r := request{method: "GET", path: "/api/v1/countries"}
+	r.query = params.values()
+	var out []Country
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p GetCountriesParams) values() url.Values {
+	query := url.Values{}
+	if p.Lang != "" {
+		query.Set("lang", p.Lang)
+	}
+	return query
+}
+
+// GetScenarios 演示支付场景
+func (c *Client) GetScenarios(ctx context.Context) ([]PaymentScenario, error) {
+	r := request{method: "GET", path: "/api/v1/scenarios"}
+	var out []PaymentScenario
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+// GetConfig 当前环境配置
+func (c *Client) GetConfig(ctx context.Context) (*ConfigInfo, error) {
+	r := request{method: "GET", path: "/api/v1/config"}
+	var out ConfigInfo
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SwitchEnvironment 切换Evonet API环境（需要管理令牌）
+func (c *Client) SwitchEnvironment(ctx context.Context, req *SwitchEnvironmentRequest) (*ConfigInfo, error) {
+	r := request{method: "POST", path: "/api/v1/config/switch-env", admin: true}
+	r.json = req
+	var out ConfigInfo
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPayments 查询本地支付记录（需要管理令牌）
+func (c *Client) ListPayments(ctx context.Context, params ListPaymentsParams) (*ListPaymentsResponse, error) {
+	r := request{method: "GET", path: "/api/v1/payments", admin: true}
+	r.query = params.values()
+	var out ListPaymentsResponse
+	if err := c.call(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (p ListPaymentsParams) values() url.Values {
+	query := url.Values{}
+	if p.Status != "" {
+		query.Set("status", p.Status)
+	}
+	if p.PaymentType != "" {
+		query.Set("paymentType", string(p.PaymentType))
+	}
+	if p.Currency != "" {
+		query.Set("currency", p.Currency)
+	}
+	if p.Environment != "" {
+		query.Set("environment", p.Environment)
+	}
+	if p.MerchantTransID != "" {
+		query.Set("merchantTransId", p.MerchantTransID)
+	}
+	if p.CreatedFrom != "" {
+		query.Set("createdFrom", p.CreatedFrom)
+	}
+	if p.CreatedTo != "" {
+		query.Set("createdTo", p.CreatedTo)
+	}
+	if p.MinAmount != nil {
+		query.Set("minAmount", strconv.FormatFloat(*p.MinAmount, 'f', -1, 64))
+	}
+	if p.MaxAmount != nil {
+		query.Set("maxAmount", strconv.FormatFloat(*p.MaxAmount, 'f', -1, 64))
+	}
+	for key, value := range p.Metadata {
+		query.Set("metadata["+key+"]", value)
+	}
+	if p.Sort != "" {
+		query.Set("sort", p.Sort)
+	}
+	if p.Order != "" {
+		query.Set("order", p.Order)
+	}
+	if p.Limit != 0 {
+		query.Set("limit", strconv.FormatInt(int64(p.Limit), 10))
+	}
+	if p.Cursor != "" {
+		query.Set("cursor", p.Cursor)
+	}
+	return query
+}
+
+// CreateInteraction 创建LinkPay/Drop-in交互
+func (c *Client) CreateInteraction(ctx context.Context, req *PaymentRequest) (*PaymentResponse, error) {
+	r := request{method: "POST", path: "/api/v1/payment/interaction"}
+	r.json = req
+	var out PaymentResponse
+	if err := c.call(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateDirectPayment 创建Direct API支付
+func (c *Client) CreateDirectPayment(ctx context.Context, req *PaymentRequest) (*PaymentResponse, error) {
+	r := request{method: "POST", path: "/api/v1/payment/direct"}
+	r.json = req
+	var out PaymentResponse
+	if err := c.call(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPaymentStatus 查询支付状态
+func (c *Client) GetPaymentStatus(ctx context.Context, merchantTransID string) (*Payment, error) {
+	r := request{method: "GET", path: "/api/v1/payment/" + url.PathEscape(merchantTransID)}
+	var out Payment
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RefundPayment 发起（部分）退款（需要管理令牌）
+func (c *Client) RefundPayment(ctx context.Context, merchantTransID string, req *RefundRequest) (*Refund, error) {
+	r := request{method: "POST", path: "/api/v1/payment/" + url.PathEscape(merchantTransID) + "/refund", admin: true}
+	r.json = req
+	var out Refund
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPaymentQR LinkPay链接的二维码（PNG/SVG）
+func (c *Client) GetPaymentQR(ctx context.Context, w io.Writer, merchantTransID string, params GetPaymentQRParams) error {
+	r := request{method: "GET", path: "/api/v1/payment/" + url.PathEscape(merchantTransID) + "/qr"}
+	r.query = params.values()
+	return c.stream(ctx, r, w)
+}
+
+func (p GetPaymentQRParams) values() url.Values {
+	query := url.Values{}
+	if p.Format != "" {
+		query.Set("format", p.Format)
+	}
+	if p.Size != 0 {
+		query.Set("size", strconv.FormatInt(int64(p.Size), 10))
+	}
+	if p.EC != "" {
+		query.Set("ec", p.EC)
+	}
+	if p.Logo {
+		query.Set("logo", "true")
+	}
+	return query
+}
+
+// GetFXRates 当前汇率表（FX_RATES_PATH文件或内置静态汇率）
+func (c *Client) GetFXRates(ctx context.Context) (*FXRates, error) {
+	r := request{method: "GET", path: "/api/v1/fx/rates"}
+	var out FXRates
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateFXQuote 将基础币种金额换算为支付币种并锁定报价
+func (c *Client) CreateFXQuote(ctx context.Context, req *FXQuoteRequest) (*FXQuote, error) {
+	r := request{method: "POST", path: "/api/v1/fx/quotes"}
+	r.json = req
+	var out FXQuote
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFXQuote 查询汇率报价
+func (c *Client) GetFXQuote(ctx context.Context, id string) (*FXQuote, error) {
+	r := request{method: "GET", path: "/api/v1/fx/quotes/" + url.PathEscape(id)}
+	var out FXQuote
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePaymentLink 创建支付链接（需要管理令牌）
+func (c *Client) CreatePaymentLink(ctx context.Context, req *PaymentLinkRequest) (*PaymentLink, error) {
+	r := request{method: "POST", path: "/api/v1/payment-links", admin: true}
+	r.json = req
+	var out PaymentLink
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPaymentLinks 支付链接列表（按创建时间倒序）（需要管理令牌）
+func (c *Client) ListPaymentLinks(ctx context.Context, params ListPaymentLinksParams) ([]PaymentLink, error) {
+	r := request{method: "GET", path: "/api/v1/payment-links", admin: true}
+	r.query = params.values()
+	var out []PaymentLink
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListPaymentLinksParams) values() url.Values {
+	query := url.Values{}
+	if p.Status != "" {
+		query.Set("status", string(p.Status))
+	}
+	return query
+}
+
+// GetPaymentLink 查询支付链接（需要管理令牌）
+func (c *Client) GetPaymentLink(ctx context.Context, id string) (*PaymentLink, error) {
+	r := request{method: "GET", path: "/api/v1/payment-links/" + url.PathEscape(id), admin: true}
+	var out PaymentLink
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeactivatePaymentLink 停用支付链接（已发起的支付不受影响）（需要管理令牌）
+func (c *Client) DeactivatePaymentLink(ctx context.Context, id string) (*PaymentLink, error) {
+	r := request{method: "POST", path: "/api/v1/payment-links/" + url.PathEscape(id) + "/deactivate", admin: true}
+	var out PaymentLink
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPaymentLinkPayments 通过支付链接发起的支付（按创建时间升序）（需要管理令牌）
+func (c *Client) ListPaymentLinkPayments(ctx context.Context, id string) ([]PaymentRecord, error) {
+	r := request{method: "GET", path: "/api/v1/payment-links/" + url.PathEscape(id) + "/payments", admin: true}
+	var out []PaymentRecord
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+// CheckoutPaymentLink 通过支付链接发起LinkPay支付
+func (c *Client) CheckoutPaymentLink(ctx context.Context, id string, req *PaymentLinkCheckout) (*PaymentResponse, error) {
+	r := request{method: "POST", path: "/api/v1/payment-links/" + url.PathEscape(id) + "/checkout"}
+	r.json = req
+	var out PaymentResponse
+	if err := c.call(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetInteractionStatus 查询LinkPay/Drop-in交互状态
+func (c *Client) GetInteractionStatus(ctx context.Context, merchantOrderID string) (*Payment, error) {
+	r := request{method: "GET", path: "/api/v1/interaction/" + url.PathEscape(merchantOrderID)}
+	var out Payment
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWebhookEndpoint 注册商户webhook地址（需要管理令牌）
+func (c *Client) CreateWebhookEndpoint(ctx context.Context, req *WebhookEndpointRequest) (*WebhookEndpoint, error) {
+	r := request{method: "POST", path: "/api/v1/webhook-endpoints", admin: true}
+	r.json = req
+	var out WebhookEndpoint
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWebhookEndpoints 商户webhook地址列表（需要管理令牌）
+func (c *Client) ListWebhookEndpoints(ctx context.Context) ([]WebhookEndpoint, error) {
+	r := request{method: "GET", path: "/api/v1/webhook-endpoints", admin: true}
+	var out []WebhookEndpoint
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+// DeleteWebhookEndpoint 删除商户webhook地址（需要管理令牌）
+func (c *Client) DeleteWebhookEndpoint(ctx context.Context, id string) error {
+	r := request{method: "DELETE", path: "/api/v1/webhook-endpoints/" + url.PathEscape(id), admin: true}
+	return c.callData(ctx, r, nil)
+}
+
+// ListWebhookDeliveries 商户webhook投递记录（需要管理令牌）
+func (c *Client) ListWebhookDeliveries(ctx context.Context, params ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	r := request{method: "GET", path: "/api/v1/webhook-deliveries", admin: true}
+	r.query = params.values()
+	var out []WebhookDelivery
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListWebhookDeliveriesParams) values() url.Values {
+	query := url.Values{}
+	if p.Status != "" {
+		query.Set("status", p.Status)
+	}
+	if p.EndpointID != "" {
+		query.Set("endpointId", p.EndpointID)
+	}
+	return query
+}
+
+// GetWebhookDelivery 查询单次投递（需要管理令牌）
+func (c *Client) GetWebhookDelivery(ctx context.Context, id string) (*WebhookDelivery, error) {
+	r := request{method: "GET", path: "/api/v1/webhook-deliveries/" + url.PathEscape(id), admin: true}
+	var out WebhookDelivery
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RedeliverWebhook 重新投递（包括死信）（需要管理令牌）
+func (c *Client) RedeliverWebhook(ctx context.Context, id string) (*WebhookDelivery, error) {
+	r := request{method: "POST", path: "/api/v1/webhook-deliveries/" + url.PathEscape(id) + "/redeliver", admin: true}
+	var out WebhookDelivery
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListDisputes 拒付列表（按创建时间倒序）（需要管理令牌）
+func (c *Client) ListDisputes(ctx context.Context, params ListDisputesParams) ([]Dispute, error) {
+	r := request{method: "GET", path: "/api/v1/disputes", admin: true}
+	r.query = params.values()
+	var out []Dispute
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListDisputesParams) values() url.Values {
+	query := url.Values{}
+	if p.Status != "" {
+		query.Set("status", string(p.Status))
+	}
+	if p.MerchantTransID != "" {
+		query.Set("merchantTransId", p.MerchantTransID)
+	}
+	return query
+}
+
+// GetDispute 查询拒付（ID为Evonet的disputeId）（需要管理令牌）
+func (c *Client) GetDispute(ctx context.Context, id string) (*Dispute, error) {
+	r := request{method: "GET", path: "/api/v1/disputes/" + url.PathEscape(id), admin: true}
+	var out Dispute
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadDisputeEvidence 上传拒付证据（仅needs_response状态且未过截止时间）（需要管理令牌）
+func (c *Client) UploadDisputeEvidence(ctx context.Context, id string, form *UploadDisputeEvidenceForm) (*DisputeEvidence, error) {
+	r := request{method: "POST", path: "/api/v1/disputes/" + url.PathEscape(id) + "/evidence", admin: true}
+	var err error
+	if r.body, r.contentType, err = form.encode(); err != nil {
+		return nil, err
+	}
+	var out DisputeEvidence
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (f *UploadDisputeEvidenceForm) encode() (io.Reader, string, error) {
+	return encodeForm([]formField{
+		{name: "file", file: f.File, fileName: f.FileName},
+		{name: "description", value: f.Description},
+	})
+}
+
+// DownloadDisputeEvidence 下载拒付证据（需要管理令牌）
+func (c *Client) DownloadDisputeEvidence(ctx context.Context, w io.Writer, id string, evidenceID string) error {
+	r := request{method: "GET", path: "/api/v1/disputes/" + url.PathEscape(id) + "/evidence/" + url.PathEscape(evidenceID), admin: true}
+	return c.stream(ctx, r, w)
+}
+
+// RespondDispute 接受拒付或提交证据应诉（需要管理令牌）
+func (c *Client) RespondDispute(ctx context.Context, id string, req *DisputeResponseRequest) (*Dispute, error) {
+	r := request{method: "POST", path: "/api/v1/disputes/" + url.PathEscape(id) + "/respond", admin: true}
+	r.json = req
+	var out Dispute
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWebhookEvents 入站webhook事件日志（需要管理令牌）
+func (c *Client) ListWebhookEvents(ctx context.Context, params ListWebhookEventsParams) ([]InboundWebhookEvent, error) {
+	r := request{method: "GET", path: "/api/v1/admin/webhook-events", admin: true}
+	r.query = params.values()
+	var out []InboundWebhookEvent
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListWebhookEventsParams) values() url.Values {
+	query := url.Values{}
+	if p.Status != "" {
+		query.Set("status", p.Status)
+	}
+	if p.MerchantTransID != "" {
+		query.Set("merchantTransId", p.MerchantTransID)
+	}
+	return query
+}
+
+// GetWebhookEvent 查询入站webhook事件（需要管理令牌）
+func (c *Client) GetWebhookEvent(ctx context.Context, id string) (*InboundWebhookEvent, error) {
+	r := request{method: "GET", path: "/api/v1/admin/webhook-events/" + url.PathEscape(id), admin: true}
+	var out InboundWebhookEvent
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReplayWebhookEvent 重新处理入站webhook事件（需要管理令牌）
+func (c *Client) ReplayWebhookEvent(ctx context.Context, id string) (*InboundWebhookEvent, error) {
+	r := request{method: "POST", path: "/api/v1/admin/webhook-events/" + url.PathEscape(id) + "/replay", admin: true}
+	var out InboundWebhookEvent
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportTransactionReport 导出交易报表（需要管理令牌）
+func (c *Client) ExportTransactionReport(ctx context.Context, w io.Writer, params ExportTransactionReportParams) error {
+	r := request{method: "GET", path: "/api/v1/admin/reports/transactions", admin: true}
+	r.query = params.values()
+	return c.stream(ctx, r, w)
+}
+
+func (p ExportTransactionReportParams) values() url.Values {
+	query := url.Values{}
+	if p.Format != "" {
+		query.Set("format", p.Format)
+	}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	return query
+}
+
+// CreateReconciliation 上传Evonet结算文件并对账（需要管理令牌）
+func (c *Client) CreateReconciliation(ctx context.Context, params CreateReconciliationParams, body io.Reader) (*Reconciliation, error) {
+	r := request{method: "POST", path: "/api/v1/admin/reconciliations", admin: true}
+	r.query = params.values()
+	r.body, r.contentType = body, "text/csv"
+	var out Reconciliation
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (p CreateReconciliationParams) values() url.Values {
+	query := url.Values{}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	if p.FileName != "" {
+		query.Set("fileName", p.FileName)
+	}
+	return query
+}
+
+// ListReconciliations 对账记录列表（不含明细）（需要管理令牌）
+func (c *Client) ListReconciliations(ctx context.Context) ([]Reconciliation, error) {
+	r := request{method: "GET", path: "/api/v1/admin/reconciliations", admin: true}
+	var out []Reconciliation
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+// GetReconciliation 查询对账结果（需要管理令牌）
+func (c *Client) GetReconciliation(ctx context.Context, id string, params GetReconciliationParams) (*Reconciliation, error) {
+	r := request{method: "GET", path: "/api/v1/admin/reconciliations/" + url.PathEscape(id), admin: true}
+	r.query = params.values()
+	var out Reconciliation
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (p GetReconciliationParams) values() url.Values {
+	query := url.Values{}
+	if p.Category != "" {
+		query.Set("category", p.Category)
+	}
+	return query
+}
+
+// ListRiskAssessments Direct API风控评估记录（需要管理令牌）
+func (c *Client) ListRiskAssessments(ctx context.Context, params ListRiskAssessmentsParams) ([]RiskAssessment, error) {
+	r := request{method: "GET", path: "/api/v1/admin/risk/assessments", admin: true}
+	r.query = params.values()
+	var out []RiskAssessment
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListRiskAssessmentsParams) values() url.Values {
+	query := url.Values{}
+	if p.Decision != "" {
+		query.Set("decision", string(p.Decision))
+	}
+	if p.MerchantTransID != "" {
+		query.Set("merchantTransId", p.MerchantTransID)
+	}
+	if p.CardFingerprint != "" {
+		query.Set("cardFingerprint", p.CardFingerprint)
+	}
+	return query
+}
+
+// GetRiskAssessment 查询风控评估（ID即merchantTransId）（需要管理令牌）
+func (c *Client) GetRiskAssessment(ctx context.Context, id string) (*RiskAssessment, error) {
+	r := request{method: "GET", path: "/api/v1/admin/risk/assessments/" + url.PathEscape(id), admin: true}
+	var out RiskAssessment
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRiskRules 当前生效的风控规则（需要管理令牌）
+func (c *Client) GetRiskRules(ctx context.Context) (map[string]interface{}, error) {
+	r := request{method: "GET", path: "/api/v1/admin/risk/rules", admin: true}
+	var out map[string]interface{}
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+// GetFeeSchedule 当前生效的手续费率表（需要管理令牌）
+func (c *Client) GetFeeSchedule(ctx context.Context) (*FeeSchedule, error) {
+	r := request{method: "GET", path: "/api/v1/admin/fees/schedule", admin: true}
+	var out FeeSchedule
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBeneficiary 创建收款人（需要管理令牌）
+func (c *Client) CreateBeneficiary(ctx context.Context, req *BeneficiaryRequest) (*Beneficiary, error) {
+	r := request{method: "POST", path: "/api/v1/admin/beneficiaries", admin: true}
+	r.json = req
+	var out Beneficiary
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBeneficiaries 收款人列表（按创建时间倒序）（需要管理令牌）
+func (c *Client) ListBeneficiaries(ctx context.Context, params ListBeneficiariesParams) ([]Beneficiary, error) {
+	r := request{method: "GET", path: "/api/v1/admin/beneficiaries", admin: true}
+	r.query = params.values()
+	var out []Beneficiary
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListBeneficiariesParams) values() url.Values {
+	query := url.Values{}
+	if p.Currency != "" {
+		query.Set("currency", p.Currency)
+	}
+	return query
+}
+
+// GetBeneficiary 查询收款人（需要管理令牌）
+func (c *Client) GetBeneficiary(ctx context.Context, id string) (*Beneficiary, error) {
+	r := request{method: "GET", path: "/api/v1/admin/beneficiaries/" + url.PathEscape(id), admin: true}
+	var out Beneficiary
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DisableBeneficiary 停用收款人（已提交的付款不受影响）（需要管理令牌）
+func (c *Client) DisableBeneficiary(ctx context.Context, id string) (*Beneficiary, error) {
+	r := request{method: "POST", path: "/api/v1/admin/beneficiaries/" + url.PathEscape(id) + "/disable", admin: true}
+	var out Beneficiary
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePayout 创建付款并提交出款（需要管理令牌）
+func (c *Client) CreatePayout(ctx context.Context, req *PayoutRequest) (*Payout, error) {
+	r := request{method: "POST", path: "/api/v1/admin/payouts", admin: true}
+	r.json = req
+	var out Payout
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPayouts 付款列表（按创建时间倒序）（需要管理令牌）
+func (c *Client) ListPayouts(ctx context.Context, params ListPayoutsParams) ([]Payout, error) {
+	r := request{method: "GET", path: "/api/v1/admin/payouts", admin: true}
+	r.query = params.values()
+	var out []Payout
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListPayoutsParams) values() url.Values {
+	query := url.Values{}
+	if p.Status != "" {
+		query.Set("status", string(p.Status))
+	}
+	if p.BeneficiaryID != "" {
+		query.Set("beneficiaryId", p.BeneficiaryID)
+	}
+	if p.Currency != "" {
+		query.Set("currency", p.Currency)
+	}
+	return query
+}
+
+// GetPayout 查询付款（需要管理令牌）
+func (c *Client) GetPayout(ctx context.Context, id string) (*Payout, error) {
+	r := request{method: "GET", path: "/api/v1/admin/payouts/" + url.PathEscape(id), admin: true}
+	var out Payout
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBalances 当前环境各币种的可付款余额（需要管理令牌）
+func (c *Client) GetBalances(ctx context.Context) ([]Balance, error) {
+	r := request{method: "GET", path: "/api/v1/admin/balances", admin: true}
+	var out []Balance
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+// ListLedgerEntries 日记账分录（按记账时间排序）（需要管理令牌）
+func (c *Client) ListLedgerEntries(ctx context.Context, params ListLedgerEntriesParams) ([]JournalEntry, error) {
+	r := request{method: "GET", path: "/api/v1/admin/ledger/entries", admin: true}
+	r.query = params.values()
+	var out []JournalEntry
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListLedgerEntriesParams) values() url.Values {
+	query := url.Values{}
+	if p.Environment != "" {
+		query.Set("environment", p.Environment)
+	}
+	if p.Currency != "" {
+		query.Set("currency", p.Currency)
+	}
+	if p.Account != "" {
+		query.Set("account", string(p.Account))
+	}
+	if p.SourceID != "" {
+		query.Set("sourceId", p.SourceID)
+	}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	return query
+}
+
+// GetLedgerBalances 某一时点的账户余额（借方为正）（需要管理令牌）
+func (c *Client) GetLedgerBalances(ctx context.Context, params GetLedgerBalancesParams) ([]LedgerBalance, error) {
+	r := request{method: "GET", path: "/api/v1/admin/ledger/balances", admin: true}
+	r.query = params.values()
+	var out []LedgerBalance
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p GetLedgerBalancesParams) values() url.Values {
+	query := url.Values{}
+	if p.At != "" {
+		query.Set("at", p.At)
+	}
+	if p.Environment != "" {
+		query.Set("environment", p.Environment)
+	}
+	if p.Currency != "" {
+		query.Set("currency", p.Currency)
+	}
+	if p.Account != "" {
+		query.Set("account", string(p.Account))
+	}
+	return query
+}
+
+// VerifyLedger 校验每笔分录借贷平衡且各币种全部分录合计为零（需要管理令牌）
+func (c *Client) VerifyLedger(ctx context.Context) (*LedgerVerification, error) {
+	r := request{method: "GET", path: "/api/v1/admin/ledger/verify", admin: true}
+	var out LedgerVerification
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BackfillLedger 为启用记账前的业务补记分录（可重复执行）（需要管理令牌）
+func (c *Client) BackfillLedger(ctx context.Context) (*BackfillLedgerData, error) {
+	r := request{method: "POST", path: "/api/v1/admin/ledger/backfill", admin: true}
+	var out BackfillLedgerData
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAuditEntries 查询审计日志（按时间倒序）（需要管理令牌）
+func (c *Client) ListAuditEntries(ctx context.Context, params ListAuditEntriesParams) ([]AuditEntry, error) {
+	r := request{method: "GET", path: "/api/v1/admin/audit", admin: true}
+	r.query = params.values()
+	var out []AuditEntry
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+func (p ListAuditEntriesParams) values() url.Values {
+	query := url.Values{}
+	if p.Action != "" {
+		query.Set("action", p.Action)
+	}
+	if p.Actor != "" {
+		query.Set("actor", p.Actor)
+	}
+	if p.TargetID != "" {
+		query.Set("targetId", p.TargetID)
+	}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	if p.Limit != 0 {
+		query.Set("limit", strconv.FormatInt(int64(p.Limit), 10))
+	}
+	return query
+}
+
+// ExportAuditLog 导出审计日志（按Seq升序，含哈希）（需要管理令牌）
+func (c *Client) ExportAuditLog(ctx context.Context, w io.Writer, params ExportAuditLogParams) error {
+	r := request{method: "GET", path: "/api/v1/admin/audit/export", admin: true}
+	r.query = params.values()
+	return c.stream(ctx, r, w)
+}
+
+func (p ExportAuditLogParams) values() url.Values {
+	query := url.Values{}
+	if p.Format != "" {
+		query.Set("format", p.Format)
+	}
+	if p.Action != "" {
+		query.Set("action", p.Action)
+	}
+	if p.Actor != "" {
+		query.Set("actor", p.Actor)
+	}
+	if p.TargetID != "" {
+		query.Set("targetId", p.TargetID)
+	}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	return query
+}
+
+// VerifyAuditLog 校验审计日志哈希链（需要管理令牌）
+func (c *Client) VerifyAuditLog(ctx context.Context) (*AuditVerification, error) {
+	r := request{method: "GET", path: "/api/v1/admin/audit/verify", admin: true}
+	var out AuditVerification
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListStoreLoadErrors 查询未确认的数据文件加载失败（需要管理令牌）
+func (c *Client) ListStoreLoadErrors(ctx context.Context) ([]StoreLoadError, error) {
+	r := request{method: "GET", path: "/api/v1/admin/store/load-errors", admin: true}
+	var out []StoreLoadError
+	err := c.callData(ctx, r, &out)
+	return out, err
+}
+
+// AcknowledgeStoreLoadError 确认数据文件加载失败（需要管理令牌）
+func (c *Client) AcknowledgeStoreLoadError(ctx context.Context, collection string) (*StoreLoadError, error) {
+	r := request{method: "POST", path: "/api/v1/admin/store/load-errors/" + url.PathEscape(collection) + "/acknowledge", admin: true}
+	var out StoreLoadError
+	if err := c.callData(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
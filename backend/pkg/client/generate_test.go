@@ -0,0 +1,30 @@
+package client
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"payment-demo/internal/clientgen"
+)
+
+// TestGeneratedUpToDate 确认*_gen.go与openapi.yaml一致，修改接口定义后需运行go generate
+func TestGeneratedUpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../internal/api/openapi.yaml")
+	if err != nil {
+		t.Fatalf("读取接口定义失败: %v", err)
+	}
+	files, err := clientgen.Generate(spec)
+	if err != nil {
+		t.Fatalf("生成客户端失败: %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("读取%s失败: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s已过期，请在backend下运行 go generate ./pkg/client", name)
+		}
+	}
+}
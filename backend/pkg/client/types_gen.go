@@ -0,0 +1,892 @@
+// Code generated by clientgen from internal/api/openapi.yaml. DO NOT EDIT.
+
+package client
+
+import (
+	"io"
+	"time"
+)
+
+// Envelope 对应接口定义中的Envelope
+type Envelope struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// FieldError 对应接口定义中的FieldError
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Pagination 对应接口定义中的Pagination
+type Pagination struct {
+	Limit      int    `json:"limit,omitempty"`
+	HasMore    bool   `json:"hasMore,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// PaymentType 对应接口定义中的PaymentType
+type PaymentType string
+
+// PaymentType的取值
+const (
+	PaymentTypeLinkpay   PaymentType = "linkpay"
+	PaymentTypeDropin    PaymentType = "dropin"
+	PaymentTypeDirectapi PaymentType = "directapi"
+)
+
+// PaymentStatus 对应接口定义中的PaymentStatus
+type PaymentStatus string
+
+// PaymentStatus的取值
+const (
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusAuthorized        PaymentStatus = "authorized"
+	PaymentStatusCaptured          PaymentStatus = "captured"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusCancelled         PaymentStatus = "cancelled"
+	PaymentStatusExpired           PaymentStatus = "expired"
+	PaymentStatusUnknown           PaymentStatus = "unknown"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+)
+
+// Country 对应接口定义中的Country
+type Country struct {
+	Code           string              `json:"code,omitempty"`
+	Name           string              `json:"name,omitempty"`
+	Currency       string              `json:"currency,omitempty"`
+	Language       string              `json:"language,omitempty"`
+	PaymentMethods map[string][]string `json:"paymentMethods,omitempty"` // 集成方式 -> 支付方式
+}
+
+// PaymentScenario 对应接口定义中的PaymentScenario
+type PaymentScenario struct {
+	ID          string      `json:"id,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	Environment string      `json:"environment,omitempty"`
+	Type        PaymentType `json:"type,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// ConfigInfo 对应接口定义中的ConfigInfo
+type ConfigInfo struct {
+	Environment string `json:"environment,omitempty"`
+	APIMode     string `json:"apiMode,omitempty"`
+	APIURL      string `json:"apiUrl,omitempty"`
+	HasAPIKeys  bool   `json:"hasApiKeys,omitempty"`
+	CurrentEnv  string `json:"currentEnv,omitempty"`
+}
+
+// CardInfo 对应接口定义中的CardInfo
+type CardInfo struct {
+	CardNumber string `json:"cardNumber"`
+	ExpiryDate string `json:"expiryDate"` // MMYY
+	CVV        string `json:"cvv,omitempty"`
+	HolderName string `json:"holderName,omitempty"`
+}
+
+// PaymentRequest 对应接口定义中的PaymentRequest
+type PaymentRequest struct {
+	Amount          float64     `json:"amount"`
+	Currency        string      `json:"currency"`
+	MerchantTransID string      `json:"merchantTransId,omitempty"` // 为空时由服务端生成；returnUrl中的{merchantTransId}会被替换
+	PaymentType     PaymentType `json:"paymentType"`
+	PaymentMethod   string      `json:"paymentMethod,omitempty"`
+	ReturnURL       string      `json:"returnUrl"`
+	WebhookURL      string      `json:"webhookUrl,omitempty"`
+	CustomerEmail   string      `json:"customerEmail,omitempty"`
+	BillingCountry  string      `json:"billingCountry,omitempty"` // ISO 3166-1 alpha-2
+	Order           *Order      `json:"order,omitempty"`
+	FXQuoteID       string      `json:"fxQuoteId,omitempty"` // 锁定的汇率报价，currency和amount必须与报价一致
+	Metadata        Metadata    `json:"metadata,omitempty"`
+	CardInfo        *CardInfo   `json:"cardInfo,omitempty"`
+}
+
+// Metadata 键为1-40个字母、数字或 _ - .，值最长500个字符
+type Metadata map[string]string
+
+// OrderItem 对应接口定义中的OrderItem
+type OrderItem struct {
+	Sku       string  `json:"sku,omitempty"`
+	Name      string  `json:"name"`
+	Category  string  `json:"category,omitempty"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unitPrice"`
+	Discount  float64 `json:"discount,omitempty"` // 本行折扣合计，不能超过 quantity × unitPrice
+	Tax       float64 `json:"tax,omitempty"`      // 本行税费合计
+}
+
+// Buyer 对应接口定义中的Buyer
+type Buyer struct {
+	ID    string `json:"id,omitempty"` // 商户侧的客户ID
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// Address 对应接口定义中的Address
+type Address struct {
+	Name       string `json:"name,omitempty"` // 收件人
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-2
+}
+
+// Order 合计 = Σ(quantity × unitPrice - discount + tax) - discount + tax + shipping，按币种小数位比较，必须等于支付金额
+type Order struct {
+	Items           []OrderItem `json:"items"`
+	Discount        float64     `json:"discount,omitempty"` // 订单级折扣
+	Tax             float64     `json:"tax,omitempty"`      // 订单级税费
+	Shipping        float64     `json:"shipping,omitempty"`
+	Buyer           *Buyer      `json:"buyer,omitempty"`
+	ShippingAddress *Address    `json:"shippingAddress,omitempty"`
+}
+
+// ActionInfo 对应接口定义中的ActionInfo
+type ActionInfo struct {
+	Type string                 `json:"type,omitempty"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// PaymentResponse 对应接口定义中的PaymentResponse
+type PaymentResponse struct {
+	Success         bool                   `json:"success,omitempty"`
+	SessionID       string                 `json:"sessionId,omitempty"`
+	LinkURL         string                 `json:"linkUrl,omitempty"`
+	MerchantTransID string                 `json:"merchantTransId,omitempty"`
+	Status          string                 `json:"status,omitempty"`
+	Message         string                 `json:"message,omitempty"`
+	Data            map[string]interface{} `json:"data,omitempty"`
+	Action          *ActionInfo            `json:"action,omitempty"`
+}
+
+// Payment 对应接口定义中的Payment
+type Payment struct {
+	MerchantTransID string            `json:"merchantTransId,omitempty"`
+	Status          PaymentStatus     `json:"status,omitempty"`
+	Amount          float64           `json:"amount,omitempty"`
+	Currency        string            `json:"currency,omitempty"`
+	CreatedAt       *time.Time        `json:"createdAt,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+}
+
+// PaymentRecord 对应接口定义中的PaymentRecord
+type PaymentRecord struct {
+	MerchantTransID     string            `json:"merchantTransId,omitempty"`
+	PaymentType         PaymentType       `json:"paymentType,omitempty"`
+	PaymentMethod       string            `json:"paymentMethod,omitempty"`
+	Status              PaymentStatus     `json:"status,omitempty"`
+	Amount              float64           `json:"amount,omitempty"`
+	Currency            string            `json:"currency,omitempty"`
+	RefundedAmount      float64           `json:"refundedAmount,omitempty"`      // 已成功的退款金额
+	PendingRefundAmount float64           `json:"pendingRefundAmount,omitempty"` // 结果未知的退款预留的金额
+	Environment         string            `json:"environment,omitempty"`
+	SessionID           string            `json:"sessionId,omitempty"`
+	LinkURL             string            `json:"linkUrl,omitempty"`
+	RiskDecision        RiskDecision      `json:"riskDecision,omitempty"`
+	PaymentLinkID       string            `json:"paymentLinkId,omitempty"`
+	Order               *Order            `json:"order,omitempty"`
+	Metadata            map[string]string `json:"metadata,omitempty"`
+	BaseAmount          float64           `json:"baseAmount,omitempty"` // 基础币种金额；amount/currency为顾客支付的币种
+	BaseCurrency        string            `json:"baseCurrency,omitempty"`
+	FXRate              float64           `json:"fxRate,omitempty"` // 1单位基础币种可兑换的支付币种数量，与基础币种相同时为空
+	FXQuoteID           string            `json:"fxQuoteId,omitempty"`
+	BillingCountry      string            `json:"billingCountry,omitempty"`
+	Fees                *Fees             `json:"fees,omitempty"`
+	RefundFees          float64           `json:"refundFees,omitempty"` // 退款产生的手续费合计（退还时为负）
+	NetAmount           float64           `json:"netAmount,omitempty"`  // amount减去已退款金额和全部手续费，扣款成功后计算
+	CreatedAt           *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt           *time.Time        `json:"updatedAt,omitempty"`
+	CapturedAt          *time.Time        `json:"capturedAt,omitempty"`
+	LastCheckedAt       *time.Time        `json:"lastCheckedAt,omitempty"`
+}
+
+// FXRates 对应接口定义中的FXRates
+type FXRates struct {
+	Base   string             `json:"base,omitempty"`
+	Rates  map[string]float64 `json:"rates,omitempty"` // 1单位base可兑换的各币种数量
+	Source string             `json:"source,omitempty"`
+	AsOf   *time.Time         `json:"asOf,omitempty"`
+}
+
+// FXQuoteRequest 对应接口定义中的FXQuoteRequest
+type FXQuoteRequest struct {
+	Amount       float64 `json:"amount"`                 // 基础币种金额
+	BaseCurrency string  `json:"baseCurrency,omitempty"` // 为空时使用FX_BASE_CURRENCY
+	Currency     string  `json:"currency"`               // 支付币种
+}
+
+// FXQuote 对应接口定义中的FXQuote
+type FXQuote struct {
+	ID              string     `json:"id,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	BaseCurrency    string     `json:"baseCurrency,omitempty"`
+	BaseAmount      float64    `json:"baseAmount,omitempty"`
+	Currency        string     `json:"currency,omitempty"`
+	Amount          float64    `json:"amount,omitempty"` // 按支付币种小数位和舍入方式舍入后的金额
+	Rate            float64    `json:"rate,omitempty"`
+	Source          string     `json:"source,omitempty"`
+	RatesAsOf       *time.Time `json:"ratesAsOf,omitempty"`
+	CreatedAt       *time.Time `json:"createdAt,omitempty"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	MerchantTransID string     `json:"merchantTransId,omitempty"`
+	UsedAt          *time.Time `json:"usedAt,omitempty"`
+}
+
+// PaymentLinkStatus 对应接口定义中的PaymentLinkStatus
+type PaymentLinkStatus string
+
+// PaymentLinkStatus的取值
+const (
+	PaymentLinkStatusActive    PaymentLinkStatus = "active"
+	PaymentLinkStatusInactive  PaymentLinkStatus = "inactive"
+	PaymentLinkStatusExpired   PaymentLinkStatus = "expired"
+	PaymentLinkStatusCompleted PaymentLinkStatus = "completed"
+)
+
+// PaymentLinkRequest 对应接口定义中的PaymentLinkRequest
+type PaymentLinkRequest struct {
+	Amount        float64    `json:"amount,omitempty"` // 固定金额；为空时由顾客输入
+	MinAmount     float64    `json:"minAmount,omitempty"`
+	MaxAmount     float64    `json:"maxAmount,omitempty"`
+	Currency      string     `json:"currency"`
+	Description   string     `json:"description,omitempty"`
+	PaymentMethod string     `json:"paymentMethod,omitempty"`
+	ReturnURL     string     `json:"returnUrl"`
+	MaxUses       int        `json:"maxUses,omitempty"`   // 0表示不限次数，1为单次链接
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"` // 为空时使用PAYMENT_LINK_DEFAULT_EXPIRY
+	Metadata      Metadata   `json:"metadata,omitempty"`
+}
+
+// PaymentLink 对应接口定义中的PaymentLink
+type PaymentLink struct {
+	ID            string            `json:"id,omitempty"`
+	URL           string            `json:"url,omitempty"` // 分享地址，打开后重定向到Evonet LinkPay页面
+	Status        PaymentLinkStatus `json:"status,omitempty"`
+	Active        bool              `json:"active,omitempty"`
+	Amount        float64           `json:"amount,omitempty"`
+	MinAmount     float64           `json:"minAmount,omitempty"`
+	MaxAmount     float64           `json:"maxAmount,omitempty"`
+	Currency      string            `json:"currency,omitempty"`
+	Description   string            `json:"description,omitempty"`
+	PaymentMethod string            `json:"paymentMethod,omitempty"`
+	ReturnURL     string            `json:"returnUrl,omitempty"`
+	MaxUses       int               `json:"maxUses,omitempty"`
+	Uses          int               `json:"uses,omitempty"` // 已扣款的支付数
+	ExpiresAt     *time.Time        `json:"expiresAt,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Environment   string            `json:"environment,omitempty"`
+	CreatedAt     *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time        `json:"updatedAt,omitempty"`
+	DeactivatedAt *time.Time        `json:"deactivatedAt,omitempty"`
+}
+
+// PaymentLinkCheckout 对应接口定义中的PaymentLinkCheckout
+type PaymentLinkCheckout struct {
+	Amount float64 `json:"amount,omitempty"` // 仅顾客输入金额的链接需要
+}
+
+// RefundRequest 对应接口定义中的RefundRequest
+type RefundRequest struct {
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// Refund 对应接口定义中的Refund
+type Refund struct {
+	RefundID        string     `json:"refundId,omitempty"`
+	MerchantTransID string     `json:"merchantTransId,omitempty"`
+	Amount          float64    `json:"amount,omitempty"`
+	Currency        string     `json:"currency,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	PaymentMethod   string     `json:"paymentMethod,omitempty"`
+	Environment     string     `json:"environment,omitempty"`
+	Message         string     `json:"message,omitempty"`
+	Fees            *Fees      `json:"fees,omitempty"`
+	CreatedAt       *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt       *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Fees 手续费明细，币种与支付相同；退款中退还的部分为负数
+type Fees struct {
+	Rule       string  `json:"rule,omitempty"` // 命中的费率规则
+	Currency   string  `json:"currency,omitempty"`
+	Percentage float64 `json:"percentage,omitempty"`
+	Fixed      float64 `json:"fixed,omitempty"`
+	FXMarkup   float64 `json:"fxMarkup,omitempty"`
+	Total      float64 `json:"total,omitempty"`
+}
+
+// FeeRule 对应接口定义中的FeeRule
+type FeeRule struct {
+	Name           string  `json:"name,omitempty"`
+	PaymentMethod  string  `json:"paymentMethod,omitempty"` // 为空或*表示任意
+	Currency       string  `json:"currency,omitempty"`
+	Country        string  `json:"country,omitempty"`
+	Percentage     float64 `json:"percentage,omitempty"`    // 按支付金额收取的百分比
+	Fixed          float64 `json:"fixed,omitempty"`         // 每笔固定费用，单位为fixedCurrency
+	FixedCurrency  string  `json:"fixedCurrency,omitempty"` // 为空时使用FX_BASE_CURRENCY
+	FXMarkup       float64 `json:"fxMarkup,omitempty"`      // 支付币种与基础币种不同时额外收取的百分比
+	RefundFixed    float64 `json:"refundFixed,omitempty"`
+	ReturnOnRefund bool    `json:"returnOnRefund,omitempty"` // 退款时按比例退还百分比和换汇费用
+}
+
+// FeeSchedule 对应接口定义中的FeeSchedule
+type FeeSchedule struct {
+	Rules []FeeRule `json:"rules,omitempty"`
+}
+
+// WebhookNotification 对应接口定义中的WebhookNotification
+type WebhookNotification struct {
+	EventID   string               `json:"eventId,omitempty"`
+	EventCode string               `json:"eventCode,omitempty"`
+	Payment   *Payment             `json:"payment,omitempty"`
+	Dispute   *DisputeNotification `json:"dispute,omitempty"`
+	Timestamp *time.Time           `json:"timestamp,omitempty"`
+}
+
+// WebhookEndpointRequest 对应接口定义中的WebhookEndpointRequest
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url"`
+	Events      []string `json:"events,omitempty"` // 为空表示订阅全部事件
+	Description string   `json:"description,omitempty"`
+	Secret      string   `json:"secret,omitempty"` // 为空时自动生成
+}
+
+// WebhookEndpoint 对应接口定义中的WebhookEndpoint
+type WebhookEndpoint struct {
+	ID          string     `json:"id,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Secret      string     `json:"secret,omitempty"`
+	Events      []string   `json:"events,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Active      bool       `json:"active,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+}
+
+// WebhookEvent 对应接口定义中的WebhookEvent
+type WebhookEvent struct {
+	ID        string                 `json:"id,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	CreatedAt *time.Time             `json:"createdAt,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// WebhookDelivery 对应接口定义中的WebhookDelivery
+type WebhookDelivery struct {
+	ID             string                   `json:"id,omitempty"`
+	EndpointID     string                   `json:"endpointId,omitempty"`
+	Event          *WebhookEvent            `json:"event,omitempty"`
+	Status         string                   `json:"status,omitempty"`
+	Attempts       int                      `json:"attempts,omitempty"` // 累计投递次数，重新投递不清零
+	NextAttemptAt  *time.Time               `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int                      `json:"lastStatusCode,omitempty"`
+	LastError      string                   `json:"lastError,omitempty"`
+	CreatedAt      *time.Time               `json:"createdAt,omitempty"`
+	UpdatedAt      *time.Time               `json:"updatedAt,omitempty"`
+	DeliveredAt    *time.Time               `json:"deliveredAt,omitempty"`
+	Redeliveries   int                      `json:"redeliveries,omitempty"`  // 手动重新投递次数
+	RoundAttempts  int                      `json:"roundAttempts,omitempty"` // 本轮（创建或最近一次重新投递之后）的投递次数，重试上限按此计算
+	History        []WebhookDeliveryAttempt `json:"history,omitempty"`       // 最近50次投递尝试，按时间顺序
+}
+
+// WebhookDeliveryAttempt 对应接口定义中的WebhookDeliveryAttempt
+type WebhookDeliveryAttempt struct {
+	Attempt    int        `json:"attempt,omitempty"` // 累计第几次投递
+	Round      int        `json:"round,omitempty"`   // 0为首轮，n为第n次重新投递之后
+	At         *time.Time `json:"at,omitempty"`
+	StatusCode int        `json:"statusCode,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// InboundWebhookEvent 对应接口定义中的InboundWebhookEvent
+type InboundWebhookEvent struct {
+	ID                string            `json:"id,omitempty"`
+	DedupKey          string            `json:"dedupKey,omitempty"`
+	EventID           string            `json:"eventId,omitempty"`
+	EventCode         string            `json:"eventCode,omitempty"`
+	MerchantTransID   string            `json:"merchantTransId,omitempty"`
+	DisputeID         string            `json:"disputeId,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty"`
+	Body              string            `json:"body,omitempty"`
+	SignatureValid    bool              `json:"signatureValid,omitempty"`
+	VerificationError string            `json:"verificationError,omitempty"`
+	Status            string            `json:"status,omitempty"`
+	Result            string            `json:"result,omitempty"`
+	Attempts          int               `json:"attempts,omitempty"`
+	DuplicateCount    int               `json:"duplicateCount,omitempty"`
+	ReceivedAt        *time.Time        `json:"receivedAt,omitempty"`
+	ProcessedAt       *time.Time        `json:"processedAt,omitempty"`
+}
+
+// ReconciliationItem 对应接口定义中的ReconciliationItem
+type ReconciliationItem struct {
+	Category        string  `json:"category,omitempty"`
+	Kind            string  `json:"kind,omitempty"`
+	MerchantTransID string  `json:"merchantTransId,omitempty"`
+	Currency        string  `json:"currency,omitempty"`
+	LocalAmount     float64 `json:"localAmount,omitempty"`
+	UpstreamAmount  float64 `json:"upstreamAmount,omitempty"`
+	LocalStatus     string  `json:"localStatus,omitempty"`
+	UpstreamStatus  string  `json:"upstreamStatus,omitempty"`
+	Note            string  `json:"note,omitempty"`
+}
+
+// Reconciliation 对应接口定义中的Reconciliation
+type Reconciliation struct {
+	ID        string                 `json:"id,omitempty"`
+	FileName  string                 `json:"fileName,omitempty"`
+	From      *time.Time             `json:"from,omitempty"`
+	To        *time.Time             `json:"to,omitempty"`
+	LineCount int                    `json:"lineCount,omitempty"`
+	Summary   *ReconciliationSummary `json:"summary,omitempty"`
+	Items     []ReconciliationItem   `json:"items,omitempty"`
+	CreatedAt *time.Time             `json:"createdAt,omitempty"`
+}
+
+// ReconciliationSummary 对应接口定义中的ReconciliationSummary
+type ReconciliationSummary struct {
+	Matched         int `json:"matched,omitempty"`
+	AmountMismatch  int `json:"amountMismatch,omitempty"`
+	StatusMismatch  int `json:"statusMismatch,omitempty"`
+	MissingLocally  int `json:"missingLocally,omitempty"`
+	MissingUpstream int `json:"missingUpstream,omitempty"`
+}
+
+// RiskDecision review只做记录，支付照常发送到Evonet，需人工事后复核；block时支付不发送并置为failed
+type RiskDecision string
+
+// RiskDecision的取值
+const (
+	RiskDecisionAllow  RiskDecision = "allow"
+	RiskDecisionReview RiskDecision = "review"
+	RiskDecisionBlock  RiskDecision = "block"
+)
+
+// RiskRuleResult 对应接口定义中的RiskRuleResult
+type RiskRuleResult struct {
+	Rule     string       `json:"rule,omitempty"`
+	Decision RiskDecision `json:"decision,omitempty"`
+	Reason   string       `json:"reason,omitempty"`
+}
+
+// DisputeStatus 对应接口定义中的DisputeStatus
+type DisputeStatus string
+
+// DisputeStatus的取值
+const (
+	DisputeStatusNeedsResponse DisputeStatus = "needs_response"
+	DisputeStatusUnderReview   DisputeStatus = "under_review"
+	DisputeStatusAccepted      DisputeStatus = "accepted"
+	DisputeStatusWon           DisputeStatus = "won"
+	DisputeStatusLost          DisputeStatus = "lost"
+)
+
+// DisputeNotification 拒付通知（eventCode为DISPUTE_OPENED、DISPUTE_UPDATED、DISPUTE_WON、DISPUTE_LOST）
+type DisputeNotification struct {
+	DisputeID       string     `json:"disputeId"`
+	MerchantTransID string     `json:"merchantTransId,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	ReasonCode      string     `json:"reasonCode,omitempty"`
+	Amount          float64    `json:"amount,omitempty"`
+	Currency        string     `json:"currency,omitempty"`
+	EvidenceDueBy   *time.Time `json:"evidenceDueBy,omitempty"`
+}
+
+// Dispute 对应接口定义中的Dispute
+type Dispute struct {
+	ID              string            `json:"id,omitempty"`
+	MerchantTransID string            `json:"merchantTransId,omitempty"`
+	Status          DisputeStatus     `json:"status,omitempty"`
+	Reason          string            `json:"reason,omitempty"`
+	ReasonCode      string            `json:"reasonCode,omitempty"`
+	Amount          float64           `json:"amount,omitempty"`
+	Currency        string            `json:"currency,omitempty"`
+	EvidenceDueBy   *time.Time        `json:"evidenceDueBy,omitempty"`
+	Evidence        []DisputeEvidence `json:"evidence,omitempty"`
+	Response        string            `json:"response,omitempty"`
+	ResponseNote    string            `json:"responseNote,omitempty"`
+	RespondedAt     *time.Time        `json:"respondedAt,omitempty"`
+	ClosedAt        *time.Time        `json:"closedAt,omitempty"`
+	Environment     string            `json:"environment,omitempty"`
+	CreatedAt       *time.Time        `json:"createdAt,omitempty"`
+	UpdatedAt       *time.Time        `json:"updatedAt,omitempty"`
+}
+
+// DisputeEvidence 对应接口定义中的DisputeEvidence
+type DisputeEvidence struct {
+	ID          string     `json:"id,omitempty"`
+	FileName    string     `json:"fileName,omitempty"`
+	ContentType string     `json:"contentType,omitempty"`
+	Size        int64      `json:"size,omitempty"`
+	SHA256      string     `json:"sha256,omitempty"`
+	Description string     `json:"description,omitempty"`
+	UploadedAt  *time.Time `json:"uploadedAt,omitempty"`
+}
+
+// DisputeResponseRequest 对应接口定义中的DisputeResponseRequest
+type DisputeResponseRequest struct {
+	Action string `json:"action"`
+	Note   string `json:"note,omitempty"`
+}
+
+// BankAccount 对应接口定义中的BankAccount
+type BankAccount struct {
+	AccountName   string `json:"accountName"`
+	AccountNumber string `json:"accountNumber"` // 响应中只保留后4位
+	BankName      string `json:"bankName,omitempty"`
+	BankCode      string `json:"bankCode,omitempty"`
+	SwiftCode     string `json:"swiftCode,omitempty"`
+}
+
+// BeneficiaryRequest 对应接口定义中的BeneficiaryRequest
+type BeneficiaryRequest struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Email       string      `json:"email,omitempty"`
+	Country     string      `json:"country"`
+	Currency    string      `json:"currency"`
+	BankAccount BankAccount `json:"bankAccount"`
+	Metadata    Metadata    `json:"metadata,omitempty"`
+}
+
+// Beneficiary 对应接口定义中的Beneficiary
+type Beneficiary struct {
+	ID          string       `json:"id,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	Type        string       `json:"type,omitempty"`
+	Email       string       `json:"email,omitempty"`
+	Country     string       `json:"country,omitempty"`
+	Currency    string       `json:"currency,omitempty"` // 只能以该币种付款
+	BankAccount *BankAccount `json:"bankAccount,omitempty"`
+	Metadata    Metadata     `json:"metadata,omitempty"`
+	Active      bool         `json:"active,omitempty"`
+	CreatedAt   *time.Time   `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time   `json:"updatedAt,omitempty"`
+}
+
+// PayoutStatus 对应接口定义中的PayoutStatus
+type PayoutStatus string
+
+// PayoutStatus的取值
+const (
+	PayoutStatusPending    PayoutStatus = "pending"
+	PayoutStatusProcessing PayoutStatus = "processing"
+	PayoutStatusPaid       PayoutStatus = "paid"
+	PayoutStatusFailed     PayoutStatus = "failed"
+	PayoutStatusReturned   PayoutStatus = "returned"
+)
+
+// PayoutRequest 对应接口定义中的PayoutRequest
+type PayoutRequest struct {
+	BeneficiaryID string   `json:"beneficiaryId"`
+	Amount        float64  `json:"amount"`
+	Currency      string   `json:"currency"`
+	Reference     string   `json:"reference,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Metadata      Metadata `json:"metadata,omitempty"`
+}
+
+// Payout 对应接口定义中的Payout
+type Payout struct {
+	ID                string       `json:"id,omitempty"`
+	BeneficiaryID     string       `json:"beneficiaryId,omitempty"`
+	Amount            float64      `json:"amount,omitempty"`
+	Currency          string       `json:"currency,omitempty"`
+	Status            PayoutStatus `json:"status,omitempty"`
+	Reference         string       `json:"reference,omitempty"`
+	Description       string       `json:"description,omitempty"`
+	Metadata          Metadata     `json:"metadata,omitempty"`
+	Provider          string       `json:"provider,omitempty"`
+	ProviderReference string       `json:"providerReference,omitempty"`
+	FailureReason     string       `json:"failureReason,omitempty"`
+	Environment       string       `json:"environment,omitempty"`
+	CreatedAt         *time.Time   `json:"createdAt,omitempty"`
+	UpdatedAt         *time.Time   `json:"updatedAt,omitempty"`
+	SubmittedAt       *time.Time   `json:"submittedAt,omitempty"`
+	CompletedAt       *time.Time   `json:"completedAt,omitempty"` // 进入paid、failed或returned的时间
+}
+
+// Balance 取自账本各账户余额；available = cash - 处理中的退款，cash已扣除手续费、退款、未结及败诉的拒付、已出款和在途付款
+type Balance struct {
+	Currency    string  `json:"currency,omitempty"`
+	Environment string  `json:"environment,omitempty"`
+	Captured    float64 `json:"captured,omitempty"`
+	Refunded    float64 `json:"refunded,omitempty"` // 已退款及处理中的退款
+	Fees        float64 `json:"fees,omitempty"`
+	Disputed    float64 `json:"disputed,omitempty"` // 未结、败诉及已接受的拒付
+	PaidOut     float64 `json:"paidOut,omitempty"`
+	InTransit   float64 `json:"inTransit,omitempty"` // pending和processing的付款
+	Cash        float64 `json:"cash,omitempty"`      // 账本cash账户余额
+	Available   float64 `json:"available,omitempty"`
+}
+
+// LedgerAccount cash、dispute_reserve、payouts_in_transit为资产，revenue为收入，refunds为收入抵减，
+// fees、chargebacks为费用，payouts为已付给收款人的资金
+type LedgerAccount string
+
+// LedgerAccount的取值
+const (
+	LedgerAccountCash             LedgerAccount = "cash"
+	LedgerAccountDisputeReserve   LedgerAccount = "dispute_reserve"
+	LedgerAccountPayoutsInTransit LedgerAccount = "payouts_in_transit"
+	LedgerAccountRevenue          LedgerAccount = "revenue"
+	LedgerAccountRefunds          LedgerAccount = "refunds"
+	LedgerAccountFees             LedgerAccount = "fees"
+	LedgerAccountChargebacks      LedgerAccount = "chargebacks"
+	LedgerAccountPayouts          LedgerAccount = "payouts"
+)
+
+// Posting 对应接口定义中的Posting
+type Posting struct {
+	Account LedgerAccount `json:"account,omitempty"`
+	Amount  float64       `json:"amount,omitempty"` // 正数为借方，负数为贷方
+}
+
+// JournalEntry 对应接口定义中的JournalEntry
+type JournalEntry struct {
+	ID          string     `json:"id,omitempty"` // <type>:<sourceId>，同一业务事件只记一次
+	Type        string     `json:"type,omitempty"`
+	Currency    string     `json:"currency,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	SourceType  string     `json:"sourceType,omitempty"`
+	SourceID    string     `json:"sourceId,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Postings    []Posting  `json:"postings,omitempty"` // 合计为零
+	PostedAt    *time.Time `json:"postedAt,omitempty"`
+}
+
+// LedgerBalance 对应接口定义中的LedgerBalance
+type LedgerBalance struct {
+	Account     LedgerAccount `json:"account,omitempty"`
+	AccountType string        `json:"accountType,omitempty"`
+	Currency    string        `json:"currency,omitempty"`
+	Environment string        `json:"environment,omitempty"`
+	Balance     float64       `json:"balance,omitempty"` // 借方余额为正
+	AsOf        *time.Time    `json:"asOf,omitempty"`
+}
+
+// LedgerVerification 对应接口定义中的LedgerVerification
+type LedgerVerification struct {
+	Valid      bool               `json:"valid,omitempty"`
+	Entries    int                `json:"entries,omitempty"`
+	Unbalanced []string           `json:"unbalanced,omitempty"` // 借贷不平衡或使用未知账户的分录ID
+	Missing    []string           `json:"missing,omitempty"`    // 按扣款、退款、拒付和付款记录应有但账本中没有的分录ID，可通过backfill补记
+	Mismatched []string           `json:"mismatched,omitempty"` // 账户或金额与业务记录不一致的分录ID
+	Totals     map[string]float64 `json:"totals,omitempty"`     // 各币种全部记账行的合计，应为0
+}
+
+// AuditEntry 对应接口定义中的AuditEntry
+type AuditEntry struct {
+	Seq        int64       `json:"seq,omitempty"`
+	Timestamp  *time.Time  `json:"timestamp,omitempty"`
+	Actor      string      `json:"actor,omitempty"` // admin、api_key:<指纹>、anonymous 或 system:<来源>
+	Action     string      `json:"action,omitempty"`
+	TargetType string      `json:"targetType,omitempty"`
+	TargetID   string      `json:"targetId,omitempty"`
+	RequestID  string      `json:"requestId,omitempty"`
+	SourceIP   string      `json:"sourceIp,omitempty"`
+	Before     interface{} `json:"before,omitempty"` // 操作前状态
+	After      interface{} `json:"after,omitempty"`  // 操作后状态
+	PrevHash   string      `json:"prevHash,omitempty"`
+	Hash       string      `json:"hash,omitempty"` // sha256(prevHash + 本条目其余字段的JSON)
+}
+
+// StoreLoadError 对应接口定义中的StoreLoadError
+type StoreLoadError struct {
+	Collection    string     `json:"collection,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	QuarantinedTo string     `json:"quarantinedTo,omitempty"` // 原数据文件移至的路径，移动失败时为空
+	FailedAt      *time.Time `json:"failedAt,omitempty"`
+}
+
+// AuditVerification 对应接口定义中的AuditVerification
+type AuditVerification struct {
+	Valid    bool   `json:"valid,omitempty"`
+	Entries  int    `json:"entries,omitempty"`
+	BrokenAt int64  `json:"brokenAt,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// RiskAssessment 对应接口定义中的RiskAssessment
+type RiskAssessment struct {
+	ID              string           `json:"id,omitempty"`
+	MerchantTransID string           `json:"merchantTransId,omitempty"`
+	Decision        RiskDecision     `json:"decision,omitempty"`
+	Rules           []RiskRuleResult `json:"rules,omitempty"`
+	Amount          float64          `json:"amount,omitempty"`
+	Currency        string           `json:"currency,omitempty"`
+	CardFingerprint string           `json:"cardFingerprint,omitempty"`
+	CardBIN         string           `json:"cardBin,omitempty"`
+	CardLast4       string           `json:"cardLast4,omitempty"`
+	BINCountry      string           `json:"binCountry,omitempty"`
+	BillingCountry  string           `json:"billingCountry,omitempty"`
+	IP              string           `json:"ip,omitempty"`
+	Email           string           `json:"email,omitempty"`
+	CreatedAt       *time.Time       `json:"createdAt,omitempty"`
+}
+
+// 以下类型由operation的参数、请求体和响应生成
+
+// GetCountriesParams GetCountries的查询参数，零值字段不发送
+type GetCountriesParams struct {
+	Lang string // 返回该语言的本地化名称
+}
+
+// ListPaymentsParams ListPayments的查询参数，零值字段不发送
+type ListPaymentsParams struct {
+	Status          string
+	PaymentType     PaymentType
+	Currency        string
+	Environment     string
+	MerchantTransID string
+	CreatedFrom     string // RFC3339时间或YYYY-MM-DD
+	CreatedTo       string // RFC3339时间或YYYY-MM-DD（包含当天）
+	MinAmount       *float64
+	MaxAmount       *float64
+	Metadata        map[string]string // metadata[key]=value，多个条件需同时匹配
+	Sort            string
+	Order           string
+	Limit           int
+	Cursor          string
+}
+
+// ListPaymentsResponse 对应接口定义中的ListPaymentsResponse
+type ListPaymentsResponse struct {
+	Data       []PaymentRecord `json:"data,omitempty"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// GetPaymentQRParams GetPaymentQR的查询参数，零值字段不发送
+type GetPaymentQRParams struct {
+	Format string // 未指定时按Accept头（image/svg+xml），否则为png
+	Size   int    // 图片边长（像素）
+	EC     string // 纠错级别；叠加logo时默认H且至少为Q
+	Logo   bool   // 在中心叠加QR_LOGO_PATH配置的logo
+}
+
+// ListPaymentLinksParams ListPaymentLinks的查询参数，零值字段不发送
+type ListPaymentLinksParams struct {
+	Status PaymentLinkStatus
+}
+
+// ListWebhookDeliveriesParams ListWebhookDeliveries的查询参数，零值字段不发送
+type ListWebhookDeliveriesParams struct {
+	Status     string
+	EndpointID string
+}
+
+// ListDisputesParams ListDisputes的查询参数，零值字段不发送
+type ListDisputesParams struct {
+	Status          DisputeStatus
+	MerchantTransID string
+}
+
+// UploadDisputeEvidenceForm UploadDisputeEvidence的multipart表单
+type UploadDisputeEvidenceForm struct {
+	File        io.Reader // PDF、PNG、JPEG、GIF或纯文本，按内容识别类型
+	FileName    string    // 上传的文件名
+	Description string
+}
+
+// ListWebhookEventsParams ListWebhookEvents的查询参数，零值字段不发送
+type ListWebhookEventsParams struct {
+	Status          string
+	MerchantTransID string
+}
+
+// ExportTransactionReportParams ExportTransactionReport的查询参数，零值字段不发送
+type ExportTransactionReportParams struct {
+	Format string
+	From   string // 默认昨天0点
+	To     string // 默认今天0点（日期格式时包含当天）
+}
+
+// CreateReconciliationParams CreateReconciliation的查询参数，零值字段不发送
+type CreateReconciliationParams struct {
+	From     string
+	To       string
+	FileName string // 请求体为原始CSV时记录的文件名
+}
+
+// GetReconciliationParams GetReconciliation的查询参数，零值字段不发送
+type GetReconciliationParams struct {
+	Category string
+}
+
+// ListRiskAssessmentsParams ListRiskAssessments的查询参数，零值字段不发送
+type ListRiskAssessmentsParams struct {
+	Decision        RiskDecision
+	MerchantTransID string
+	CardFingerprint string
+}
+
+// ListBeneficiariesParams ListBeneficiaries的查询参数，零值字段不发送
+type ListBeneficiariesParams struct {
+	Currency string
+}
+
+// ListPayoutsParams ListPayouts的查询参数，零值字段不发送
+type ListPayoutsParams struct {
+	Status        PayoutStatus
+	BeneficiaryID string
+	Currency      string
+}
+
+// ListLedgerEntriesParams ListLedgerEntries的查询参数，零值字段不发送
+type ListLedgerEntriesParams struct {
+	Environment string
+	Currency    string
+	Account     LedgerAccount // 包含该账户记账行的分录
+	SourceID    string        // merchantTransId、refundId、disputeId或付款ID
+	From        string        // RFC3339或YYYY-MM-DD（含）
+	To          string        // RFC3339或YYYY-MM-DD（日期含当天）
+}
+
+// GetLedgerBalancesParams GetLedgerBalances的查询参数，零值字段不发送
+type GetLedgerBalancesParams struct {
+	At          string // RFC3339或YYYY-MM-DD（日期表示当天结束时），默认当前
+	Environment string // 默认当前环境
+	Currency    string
+	Account     LedgerAccount
+}
+
+// ListAuditEntriesParams ListAuditEntries的查询参数，零值字段不发送
+type ListAuditEntriesParams struct {
+	Action   string
+	Actor    string
+	TargetID string
+	From     string
+	To       string
+	Limit    int
+}
+
+// ExportAuditLogParams ExportAuditLog的查询参数，零值字段不发送
+type ExportAuditLogParams struct {
+	Format   string
+	Action   string
+	Actor    string
+	TargetID string
+	From     string
+	To       string
+}
+
+// SwitchEnvironmentRequest 对应接口定义中的SwitchEnvironmentRequest
+type SwitchEnvironmentRequest struct {
+	Environment string `json:"environment"`
+}
+
+// BackfillLedgerData 对应接口定义中的BackfillLedgerData
+type BackfillLedgerData struct {
+	Posted int `json:"posted,omitempty"` // 新写入的分录数
+}
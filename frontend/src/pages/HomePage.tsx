@@ -56,10 +56,15 @@ const HomePage: React.FC<HomePageProps> = () => {
 
   const handleEnvironmentSwitch = async (checked: boolean) => {
     const targetEnv = checked ? 'production' : 'sandbox';
+    // 切换环境影响整个服务，需要管理令牌
+    const adminToken = window.prompt('请输入管理令牌 (ADMIN_TOKEN)');
+    if (!adminToken) {
+      return;
+    }
     setEnvironmentSwitching(true);
     
     try {
-      await apiService.switchEnvironment(targetEnv);
+      await apiService.switchEnvironment(targetEnv, adminToken);
       message.success(`已切换到 ${targetEnv === 'production' ? 'Production' : 'Sandbox'} 环境`);
       // 重新加载页面以获取最新配置
       window.location.reload();
@@ -52,10 +52,12 @@ export const apiService = {
     return response.data.data;
   },
 
-  // 切换API环境
-  switchEnvironment: async (environment: 'sandbox' | 'production'): Promise<any> => {
+  // 切换API环境（需要管理令牌）
+  switchEnvironment: async (environment: 'sandbox' | 'production', adminToken: string): Promise<any> => {
     console.log(`切换环境到: ${environment}`);
-    const response = await api.post('/config/switch-env', { environment });
+    const response = await api.post('/config/switch-env', { environment }, {
+      headers: { 'X-Admin-Token': adminToken },
+    });
     return response.data.data;
   },
 